@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/refbook"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	maxCompletionsLimit  = 50
+	maxAutocompleteQuery = 100
+	historyNamesLimit    = 1000 // Сколько самых частых названий из списков пользователя учитывается
+)
+
+type AutocompleteController struct {
+	authService  *auth.Service
+	dataService  store.DataService
+	productIndex *refbook.Index
+}
+
+func NewAutocompleteController(
+	authService *auth.Service,
+	dataService store.DataService,
+	productIndex *refbook.Index) *AutocompleteController {
+	return &AutocompleteController{
+		authService:  authService,
+		dataService:  dataService,
+		productIndex: productIndex}
+}
+
+func (s *AutocompleteController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "Autocomplete",
+			Method: "GET",
+			Path:   "/autocomplete",
+			Func:   s.complete,
+		},
+	}
+}
+
+func (s *AutocompleteController) complete(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" || utf8.RuneCountInString(query) > maxAutocompleteQuery {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong query"), "wrong query", api.ErrValidationData)
+		return
+	}
+
+	limit := refbook.DefaultCompletions
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 || limit > maxCompletionsLimit {
+			api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong limit"), "wrong limit", api.ErrValidationData)
+			return
+		}
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	matcher, err := s.productIndex.Matcher()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get products", api.ErrInternal)
+		return
+	}
+
+	candidates := matcher.CompletionCandidates()
+
+	userProductsReadRepository := s.dataService.UserProductsReadRepository()
+
+	userProducts, err := userProductsReadRepository.GetUpdatedUserProductsForUser(currentUser.ID, 0)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get user products", api.ErrInternal)
+			return
+		}
+	}
+
+	for _, product := range userProducts {
+		if product.IsDeleted {
+			continue
+		}
+
+		candidate := refbook.CompletionCandidate{
+			Name:   product.Name,
+			Source: refbook.CompletionSourceHistory,
+		}
+
+		if product.CategoryID != 0 {
+			candidate.CategoryID = models.NewNullInt64(product.CategoryID)
+		}
+
+		if product.GlobalProductId != 0 {
+			candidate.ProductID = models.NewNullInt64(product.GlobalProductId)
+		}
+
+		if product.IsFavorite {
+			candidate.Source = refbook.CompletionSourceFavorite
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	names, err := itemsReadRepository.GetItemNamesForUser(currentUser.ID, historyNamesLimit)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get items history", api.ErrInternal)
+		return
+	}
+
+	for _, name := range names {
+		candidates = append(candidates, refbook.CompletionCandidate{
+			Name:       name.Name,
+			ProductID:  name.ProductID,
+			CategoryID: name.CategoryID,
+			Source:     refbook.CompletionSourceHistory,
+			Count:      name.Count,
+		})
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, refbook.Complete(query, candidates, limit))
+}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/refbook"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+)
+
+type BarcodesController struct {
+	authService           *auth.Service
+	dataService           store.DataService
+	categoriesRepository  repositories.RefbookCategoriesRepository
+	productsRepository    repositories.RefbookProductsRepository
+	barcodesRepository    repositories.RefbookBarcodesRepository
+	submissionsRepository repositories.BarcodeSubmissionsRepository
+	translator            *refbook.Translator
+}
+
+func NewBarcodesController(
+	authService *auth.Service,
+	dataService store.DataService,
+	categoriesRepository repositories.RefbookCategoriesRepository,
+	productsRepository repositories.RefbookProductsRepository,
+	barcodesRepository repositories.RefbookBarcodesRepository,
+	submissionsRepository repositories.BarcodeSubmissionsRepository,
+	translator *refbook.Translator) *BarcodesController {
+	return &BarcodesController{
+		authService:           authService,
+		dataService:           dataService,
+		categoriesRepository:  categoriesRepository,
+		productsRepository:    productsRepository,
+		barcodesRepository:    barcodesRepository,
+		submissionsRepository: submissionsRepository,
+		translator:            translator}
+}
+
+// Результат поиска по штрихкоду.
+// Если товара нет в справочнике, возвращается товар пользователя, к которому он привязал штрихкод
+type BarcodeLookupResponse struct {
+	Barcode     string                  `json:"barcode"`
+	Found       bool                    `json:"found"`
+	Product     *models.RefbookProduct  `json:"product"`
+	Category    *models.RefbookCategory `json:"category"`
+	UserProduct *models.UserProduct     `json:"user_product"`
+}
+
+type BarcodeSubmissionForm struct {
+	UserProductID string `json:"user_product_id"`
+}
+
+func (s *BarcodesController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "BarcodeLookup",
+			Method: "GET",
+			Path:   "/refbook/barcodes/{barcode}",
+			Func:   s.lookup,
+		},
+		{
+			Name:   "BarcodeSubmit",
+			Method: "POST",
+			Path:   "/refbook/barcodes/{barcode}",
+			Func:   s.submit,
+		},
+	}
+}
+
+func (s *BarcodesController) lookup(w http.ResponseWriter, r *http.Request) {
+	barcode, ok := refbook.NormalizeBarcode(mux.Vars(r)["barcode"])
+	if !ok {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong barcode"), "wrong barcode", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	response := BarcodeLookupResponse{Barcode: barcode}
+
+	productId, err := s.barcodesRepository.GetProductId(barcode)
+	if err == nil {
+		product, category, err := s.getProduct(productId, i18n.Negotiate(r.Header.Get("Accept-Language")))
+		if err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get product", api.ErrInternal)
+			return
+		}
+
+		response.Found = true
+		response.Product = &product
+		response.Category = &category
+
+		api.SendDataJSON(w, r, http.StatusOK, response)
+		return
+	}
+
+	if _, ok := err.(repositories.ErrNotFound); !ok {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get barcode", api.ErrInternal)
+		return
+	}
+
+	submission, err := s.submissionsRepository.GetForUser(barcode, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendDataJSON(w, r, http.StatusOK, response)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get barcode submission", api.ErrInternal)
+		return
+	}
+
+	userProductsRepository := s.dataService.UserProductsRepository(nil)
+
+	userProduct, err := userProductsRepository.GetOneById(submission.UserProductID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendDataJSON(w, r, http.StatusOK, response)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get user product", api.ErrInternal)
+		return
+	}
+
+	if !userProduct.IsDeleted && userProduct.OwnerID == currentUser.ID {
+		response.Found = true
+		response.UserProduct = &userProduct
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, response)
+}
+
+// Привязать штрихкод, которого нет в справочнике, к товару пользователя.
+// Привязки копятся и используются для пополнения справочника
+func (s *BarcodesController) submit(w http.ResponseWriter, r *http.Request) {
+	barcode, ok := refbook.NormalizeBarcode(mux.Vars(r)["barcode"])
+	if !ok {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong barcode"), "wrong barcode", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var form BarcodeSubmissionForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	err = validation.Validate(form.UserProductID, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format user product id", api.ErrValidationData)
+		return
+	}
+
+	_, err = s.barcodesRepository.GetProductId(barcode)
+	if err == nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("barcode already exists"), "barcode already exists in refbook", api.ErrValidationData)
+		return
+	}
+
+	if _, ok := err.(repositories.ErrNotFound); !ok {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get barcode", api.ErrInternal)
+		return
+	}
+
+	userProductsRepository := s.dataService.UserProductsRepository(nil)
+
+	userProduct, err := userProductsRepository.GetOneById(form.UserProductID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "user product not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get user product", api.ErrInternal)
+		return
+	}
+
+	if userProduct.OwnerID != currentUser.ID || userProduct.IsDeleted {
+		api.SendErrorJSON(w, r, http.StatusNotFound, errors.New("user product not found"), "user product not found", api.ErrNoPermission)
+		return
+	}
+
+	submission := models.BarcodeSubmission{
+		Barcode:       barcode,
+		UserID:        currentUser.ID,
+		UserProductID: userProduct.ID,
+		Name:          userProduct.Name,
+		CategoryID:    userProduct.CategoryID,
+	}
+
+	if err := s.submissionsRepository.Save(&submission); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save barcode submission", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, submission)
+}
+
+func (s *BarcodesController) getProduct(productId int64, locale string) (models.RefbookProduct, models.RefbookCategory, error) {
+	product, err := s.productsRepository.GetOneById(productId)
+	if err != nil {
+		return models.RefbookProduct{}, models.RefbookCategory{}, err
+	}
+
+	category, err := s.categoriesRepository.GetOneById(product.CategoryId)
+	if err != nil {
+		return models.RefbookProduct{}, models.RefbookCategory{}, err
+	}
+
+	products := []models.RefbookProduct{product}
+	if err := s.translator.TranslateProducts(locale, products); err != nil {
+		return models.RefbookProduct{}, models.RefbookCategory{}, err
+	}
+
+	categories := []models.RefbookCategory{category}
+	if err := s.translator.TranslateCategories(locale, categories); err != nil {
+		return models.RefbookProduct{}, models.RefbookCategory{}, err
+	}
+
+	return products[0], categories[0], nil
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"encoding/json"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+	"time"
+)
+
+type BudgetsController struct {
+	authService           *auth.Service
+	dataService           store.DataService
+	budgetsRepository     repositories.BudgetsRepository
+	budgetsReadRepository readModels.BudgetsReadRepository
+}
+
+func NewBudgetsController(
+	authService *auth.Service,
+	dataService store.DataService,
+	budgetsRepository repositories.BudgetsRepository,
+	budgetsReadRepository readModels.BudgetsReadRepository) *BudgetsController {
+	return &BudgetsController{
+		authService:           authService,
+		dataService:           dataService,
+		budgetsRepository:     budgetsRepository,
+		budgetsReadRepository: budgetsReadRepository}
+}
+
+type BudgetForm struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (s *BudgetsController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "Budgets",
+			Method: "GET",
+			Path:   "/budgets",
+			Func:   s.getBudgets,
+		},
+		{
+			Name:   "MonthlyBudgetSave",
+			Method: "PUT",
+			Path:   "/budgets/monthly",
+			Func:   s.saveMonthlyBudget,
+		},
+		{
+			Name:   "MonthlyBudgetDelete",
+			Method: "DELETE",
+			Path:   "/budgets/monthly",
+			Func:   s.deleteMonthlyBudget,
+		},
+		{
+			Name:   "ListBudgetSave",
+			Method: "PUT",
+			Path:   "/lists/{list_id}/budget",
+			Func:   s.saveListBudget,
+		},
+		{
+			Name:   "ListBudgetDelete",
+			Method: "DELETE",
+			Path:   "/lists/{list_id}/budget",
+			Func:   s.deleteListBudget,
+		},
+	}
+}
+
+func (s *BudgetsController) getBudgets(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	budgets, err := s.budgetsReadRepository.GetActiveForOwner(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get budgets", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, budgets)
+}
+
+func (s *BudgetsController) saveMonthlyBudget(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	s.save(w, r, currentUser.ID, "")
+}
+
+func (s *BudgetsController) deleteMonthlyBudget(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	s.delete(w, r, currentUser.ID, "")
+}
+
+func (s *BudgetsController) saveListBudget(w http.ResponseWriter, r *http.Request) {
+	list, ok := s.getOwnList(w, r)
+	if !ok {
+		return
+	}
+
+	s.save(w, r, list.OwnerID, list.ID)
+}
+
+func (s *BudgetsController) deleteListBudget(w http.ResponseWriter, r *http.Request) {
+	list, ok := s.getOwnList(w, r)
+	if !ok {
+		return
+	}
+
+	s.delete(w, r, list.OwnerID, list.ID)
+}
+
+func (s *BudgetsController) save(w http.ResponseWriter, r *http.Request, ownerId string, listId string) {
+	var form BudgetForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	now := time.Now().UTC().Unix()
+
+	budget, err := s.budgetsReadRepository.Get(ownerId, listId)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get budget", api.ErrInternal)
+			return
+		}
+
+		budget = models.Budget{OwnerID: ownerId, ListID: listId, CreatedAt: now}
+	}
+
+	budget.Amount = form.Amount
+	budget.Currency = form.Currency
+	budget.UpdatedAt = now
+	budget.IsDeleted = false
+
+	if budget.Currency == "" {
+		budget.Currency = models.DefaultCurrency
+	}
+
+	if _, err := budget.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	// Бюджет изменился, превышение проверяется заново
+	budget.NotifiedAt = 0
+
+	if err := s.budgetsRepository.Save(&budget); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save budget", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, budget)
+}
+
+func (s *BudgetsController) delete(w http.ResponseWriter, r *http.Request, ownerId string, listId string) {
+	budget, err := s.budgetsReadRepository.Get(ownerId, listId)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "budget not found", api.ErrValidationData)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get budget", api.ErrInternal)
+		return
+	}
+
+	budget.IsDeleted = true
+	budget.UpdatedAt = time.Now().UTC().Unix()
+
+	if err := s.budgetsRepository.Save(&budget); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't delete budget", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+// Вернуть список текущего пользователя из параметров запроса.
+// Бюджет списка может менять только владелец списка
+func (s *BudgetsController) getOwnList(w http.ResponseWriter, r *http.Request) (*models.List, bool) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return nil, false
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return nil, false
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListForIdAndOwner(listId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return nil, false
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return nil, false
+	}
+
+	if list.IsDeleted {
+		api.SendErrorJSON(w, r, http.StatusNotFound, repositories.ErrNotFound{}, "list not found", api.ErrNoPermission)
+		return nil, false
+	}
+
+	return &list, true
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+)
+
+type ContactsController struct {
+	authService    *auth.Service
+	repository     repositories.ContactAliasesRepository
+	readRepository readModels.ContactAliasesReadRepository
+}
+
+func NewContactsController(
+	authService *auth.Service,
+	repository repositories.ContactAliasesRepository,
+	readRepository readModels.ContactAliasesReadRepository) *ContactsController {
+	return &ContactsController{authService: authService, repository: repository, readRepository: readRepository}
+}
+
+type ContactAliasForm struct {
+	Alias string `json:"alias"`
+}
+
+// Routes returns slice of server routes
+func (s *ContactsController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "ContactAliases",
+			Method: "GET",
+			Path:   "/contacts/aliases",
+			Func:   s.getAliases,
+		},
+		{
+			Name:   "ContactAliasSave",
+			Method: "PUT",
+			Path:   "/contacts/{user_id}/alias",
+			Func:   s.saveAlias,
+		},
+		{
+			Name:   "ContactAliasDelete",
+			Method: "DELETE",
+			Path:   "/contacts/{user_id}/alias",
+			Func:   s.deleteAlias,
+		},
+	}
+}
+
+func (s *ContactsController) getAliases(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	aliases, err := s.readRepository.GetForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get contact aliases", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, aliases)
+}
+
+func (s *ContactsController) saveAlias(w http.ResponseWriter, r *http.Request) {
+	contactUserId := mux.Vars(r)["user_id"]
+	if !govalidator.IsUUID(contactUserId) {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong user id"), "wrong format user id", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var form ContactAliasForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	alias := models.ContactAlias{UserId: contactUserId, Alias: form.Alias}
+	if _, err = alias.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	if err = s.repository.Save(currentUser.ID, &alias); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save contact alias", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, alias)
+}
+
+func (s *ContactsController) deleteAlias(w http.ResponseWriter, r *http.Request) {
+	contactUserId := mux.Vars(r)["user_id"]
+	if !govalidator.IsUUID(contactUserId) {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong user id"), "wrong format user id", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	if err = s.repository.Delete(currentUser.ID, contactUserId); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't delete contact alias", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
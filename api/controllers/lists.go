@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/refbook"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+	"time"
+)
+
+type ListsController struct {
+	authService            *auth.Service
+	dataService            store.DataService
+	spendingReadRepository readModels.SpendingReadRepository
+	ProductIndex           *refbook.Index // Если не задан, то дубли ищутся только по названию
+}
+
+func NewListsController(
+	authService *auth.Service,
+	dataService store.DataService,
+	spendingReadRepository readModels.SpendingReadRepository) *ListsController {
+	return &ListsController{
+		authService:            authService,
+		dataService:            dataService,
+		spendingReadRepository: spendingReadRepository}
+}
+
+// Итоги по списку
+type ListTotalsResponse struct {
+	ListID  string               `json:"list_id"`
+	Totals  []models.ListTotal   `json:"totals"`
+	Members []models.MemberSpend `json:"members"`
+}
+
+// Период для отчета о тратах. Если не указан, то берется текущий месяц по UTC
+type SpendRequest struct {
+	From int64 `schema:"from"`
+	To   int64 `schema:"to"`
+}
+
+// Товары списка, сгруппированные по категориям в порядке обхода магазина
+type StoreItemsResponse struct {
+	ListID  string              `json:"list_id"`
+	StoreID string              `json:"store_id"`
+	Groups  []models.ItemsGroup `json:"groups"`
+}
+
+type SpendResponse struct {
+	From  int64              `json:"from"`
+	To    int64              `json:"to"`
+	Lists []models.ListSpend `json:"lists"`
+}
+
+func (s *ListsController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "ListTotals",
+			Method: "GET",
+			Path:   "/lists/{list_id}/totals",
+			Func:   s.getTotals,
+		},
+		{
+			Name:   "ListItemsForStore",
+			Method: "GET",
+			Path:   "/lists/{list_id}/stores/{store_id}/items",
+			Func:   s.getItemsForStore,
+		},
+		{
+			Name:   "GetListDuplicates",
+			Method: "GET",
+			Path:   "/lists/{list_id}/duplicates",
+			Func:   s.getDuplicates,
+		},
+		{
+			Name:   "Spend",
+			Method: "GET",
+			Path:   "/spend",
+			Func:   s.getSpend,
+		},
+	}
+}
+
+func (s *ListsController) getTotals(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListAccessibleForUser(listId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return
+	}
+
+	totals, err := s.spendingReadRepository.GetListTotals(list.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list totals", api.ErrInternal)
+		return
+	}
+
+	members, err := s.spendingReadRepository.GetListMemberTotals(list.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list member totals", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, ListTotalsResponse{ListID: list.ID, Totals: totals, Members: members})
+}
+
+func (s *ListsController) getItemsForStore(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+	storeId := vars["store_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	err = validation.Validate(storeId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format store id", api.ErrDecode)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListAccessibleForUser(listId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return
+	}
+
+	storesReadRepository := s.dataService.GetStoresReadRepository()
+
+	store, err := storesReadRepository.GetStoreForOwner(storeId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "store not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get store", api.ErrInternal)
+		return
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	items, err := itemsReadRepository.GetItemsForList(list.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get items", api.ErrInternal)
+		return
+	}
+
+	var listItems []models.ListItem
+	if items != nil {
+		listItems = *items
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, StoreItemsResponse{
+		ListID:  list.ID,
+		StoreID: store.ID,
+		Groups:  store.GroupItems(listItems),
+	})
+}
+
+// Найти в списке товары, которые добавлены несколько раз под разными названиями
+func (s *ListsController) getDuplicates(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListAccessibleForUser(listId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	items, err := itemsReadRepository.GetItemsForList(list.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get items", api.ErrInternal)
+		return
+	}
+
+	var listItems []models.ListItem
+	if items != nil {
+		listItems = *items
+	}
+
+	var matcher *refbook.Matcher
+	if s.ProductIndex != nil {
+		matcher, err = s.ProductIndex.Matcher()
+		if err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get products", api.ErrInternal)
+			return
+		}
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, refbook.FindDuplicates(listItems, matcher))
+}
+
+func (s *ListsController) getSpend(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var query SpendRequest
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	if err = decoder.Decode(&query, r.URL.Query()); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't query params", api.ErrDecode)
+		return
+	}
+
+	now := time.Now().UTC()
+	if query.From == 0 {
+		query.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	if query.To == 0 {
+		query.To = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	if query.To <= query.From {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong period"), "to must be greater than from", api.ErrValidationData)
+		return
+	}
+
+	spends, err := s.spendingReadRepository.GetSpendForUser(currentUser.ID, query.From, query.To)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get spend", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, SpendResponse{From: query.From, To: query.To, Lists: spends})
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"github.com/asaskevich/govalidator"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
 	"net/http"
 	"shopingList/api"
 	"shopingList/api/auth"
+	"shopingList/pkg/i18n"
 	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
 	"shopingList/pkg/readModels"
 	"shopingList/pkg/repositories"
 	"shopingList/pkg/services"
@@ -20,6 +23,7 @@ type NotificationController struct {
 	repository     repositories.NotificationsRepository
 	readRepository readModels.NotificationsReadRepository
 	PushChannel    chan services.PushNotificationMessage
+	Renderer       *notifications.Renderer // Если не задан, показываются сохраненные тексты
 }
 
 func NewNotificationController(
@@ -42,33 +46,136 @@ func (s *NotificationPushForm) Validate() (bool, error) {
 	return true, nil
 }
 
+const maxNotificationsBatch = 100
+
+type NotificationReadForm struct {
+	IDs []string `json:"ids"`
+}
+
+func (s *NotificationReadForm) Validate() (bool, error) {
+	if len(s.IDs) == 0 {
+		return false, errors.New("ids is empty")
+	}
+
+	if len(s.IDs) > maxNotificationsBatch {
+		return false, errors.New("too many ids")
+	}
+
+	for _, id := range s.IDs {
+		if !govalidator.IsUUID(id) {
+			return false, errors.New("wrong id: " + id)
+		}
+	}
+
+	return true, nil
+}
+
 type NotificationBatchForm struct {
 	Page int `json:"page"`
 }
 
 func (s *NotificationBatchForm) Validate() (bool, error) {
-	if s.Page < 0 {
+	if s.Page < 0 || s.Page > maxLegacyNotificationsPage {
 		return false, errors.New("page is wrong")
 	}
 
 	return true, nil
 }
 
-// Routes returns slice of server routes
+// Устаревшая лента отдает страницы по 10 уведомлений и не дальше maxLegacyNotificationsPage страницы
+const legacyNotificationsLimit = 10
+const maxLegacyNotificationsPage = 50
+
+const defaultNotificationsLimit = 20
+
+// Параметры ленты уведомлений: ?cursor=...&limit=20&type=4&type=5&list_id=...
+type NotificationsQuery struct {
+	Cursor string `schema:"cursor"`
+	Limit  int    `schema:"limit"`
+	Types  []int  `schema:"type"`
+	ListId string `schema:"list_id"`
+}
+
+func (s *NotificationsQuery) Validate() (bool, error) {
+	if s.Limit < 0 || s.Limit > maxNotificationsBatch {
+		return false, errors.New("limit is wrong")
+	}
+
+	for _, t := range s.Types {
+		if !models.IsNotificationType(models.NotificationType(t)) && t != models.NotificationTypeGoodsDigest {
+			return false, errors.New("unknown type: " + strconv.Itoa(t))
+		}
+	}
+
+	if s.ListId != "" && !govalidator.IsUUID(s.ListId) {
+		return false, errors.New("list_id is wrong")
+	}
+
+	return true, nil
+}
+
+func (s *NotificationsQuery) Filter() models.NotificationFilter {
+	filter := models.NotificationFilter{ListId: s.ListId}
+	for _, t := range s.Types {
+		filter.Types = append(filter.Types, models.NotificationType(t))
+	}
+
+	return filter
+}
+
+// Routes returns slice of server routes.
+// Лента уведомлений - /notifications/feed. /notifications/ и /notifications/page/{page} устарели
+// и оставлены для выпущенных клиентов, их нужно удалить, когда эти версии перестанут поддерживаться
 func (s *NotificationController) Routes() []api.Route {
 	return []api.Route{
 		{
 			Name:   "Notification",
 			Method: "GET",
 			Path:   "/notifications/page/{page}",
-			Func:   s.getNotifications,
+			Func:   s.getNotificationsBatch,
 		},
 		{
 			Name:   "Notification",
 			Method: "GET",
 			Path:   "/notifications/",
+			Func:   s.getNotificationsBatch,
+		},
+		{
+			Name:   "NotificationsFeed",
+			Method: "GET",
+			Path:   "/notifications/feed",
 			Func:   s.getNotifications,
 		},
+		{
+			Name:   "NotificationsUnreadCount",
+			Method: "GET",
+			Path:   "/notifications/unread-count",
+			Func:   s.getUnreadCount,
+		},
+		{
+			Name:   "NotificationsRead",
+			Method: "POST",
+			Path:   "/notifications/read",
+			Func:   s.markRead,
+		},
+		{
+			Name:   "NotificationsReadAll",
+			Method: "POST",
+			Path:   "/notifications/read-all",
+			Func:   s.markAllRead,
+		},
+		{
+			Name:   "NotificationRead",
+			Method: "POST",
+			Path:   "/notifications/{id}/read",
+			Func:   s.markOneRead,
+		},
+		{
+			Name:   "NotificationDelete",
+			Method: "DELETE",
+			Path:   "/notifications/{id}",
+			Func:   s.deleteNotification,
+		},
 		{
 			Name:   "NotificationPush",
 			Method: "POST",
@@ -83,7 +190,25 @@ type NotificationBatch struct {
 	Items *[]models.Notification `json:"items"`
 }
 
-func (s *NotificationController) getNotifications(w http.ResponseWriter, r *http.Request) {
+type NotificationPage struct {
+	Items      []models.Notification `json:"items"`
+	NextCursor string                `json:"next_cursor"` // Пустой, если загружена вся лента
+}
+
+type NotificationsUnreadResponse struct {
+	Unread int `json:"unread"`
+}
+
+type NotificationsUpdatedResponse struct {
+	Updated int64 `json:"updated"`
+	Unread  int   `json:"unread"`
+}
+
+// Deprecated: устаревшая постраничная лента для выпущенных клиентов, удалить вместе с маршрутами.
+// Новые клиенты используют /notifications/feed.
+// Страницы читаются тем же запросом по курсору, без COUNT и OFFSET. Total не считается:
+// он больше загруженного количества, пока есть следующая страница, этого достаточно клиентам для подгрузки
+func (s *NotificationController) getNotificationsBatch(w http.ResponseWriter, r *http.Request) {
 	currentUser, err := GetAuthorizedUser(s.authService, r)
 	if err != nil {
 		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
@@ -113,39 +238,249 @@ func (s *NotificationController) getNotifications(w http.ResponseWriter, r *http
 		return
 	}
 
-	limit := 10
-
 	var items []models.Notification
+	var cursor *models.NotificationCursor
 
 	response := NotificationBatch{Total: 0, Items: &items}
 
-	total, err := s.readRepository.GetTotalForUser(currentUser.ID)
+	// Предыдущие страницы пропускаются по курсору
+	for skip := 1; skip < page; skip++ {
+		skipped, err := s.readRepository.GetPageForUser(currentUser.ID, models.NotificationFilter{}, cursor,
+			legacyNotificationsLimit)
+		if err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notifications for user", api.ErrInternal)
+			return
+		}
+
+		response.Total += len(skipped)
+
+		if len(skipped) < legacyNotificationsLimit {
+			api.SendDataJSON(w, r, http.StatusOK, response)
+			return
+		}
+
+		last := skipped[len(skipped)-1]
+		cursor = &models.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	items, err = s.readRepository.GetPageForUser(currentUser.ID, models.NotificationFilter{}, cursor,
+		legacyNotificationsLimit+1)
 	if err != nil {
-		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get total notifications for user", api.ErrInternal)
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notifications for user", api.ErrInternal)
 		return
 	}
 
-	if total == 0 {
-		api.SendDataJSON(w, r, http.StatusOK, response)
+	response.Total += len(items)
+
+	if len(items) > legacyNotificationsLimit {
+		items = items[:legacyNotificationsLimit]
+	}
+
+	if err = s.render(r, currentUser.ID, items); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't render notifications", api.ErrInternal)
 		return
 	}
 
-	response.Total = total
+	api.SendDataJSON(w, r, http.StatusOK, response)
+}
 
-	items, err = s.readRepository.GetBatchForUser(currentUser.ID, page, limit)
+// Лента уведомлений по курсору с фильтрами по типу и списку
+func (s *NotificationController) getNotifications(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
 	if err != nil {
-		if _, ok := err.(repositories.ErrNotFound); !ok {
-			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notifications for user", api.ErrInternal)
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var query NotificationsQuery
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	if err = decoder.Decode(&query, r.URL.Query()); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't query params", api.ErrDecode)
+		return
+	}
+
+	if _, err = query.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	var cursor *models.NotificationCursor
+	if query.Cursor != "" {
+		decoded, err := models.DecodeNotificationCursor(query.Cursor)
+		if err != nil {
+			api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
 			return
 		}
+		cursor = &decoded
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultNotificationsLimit
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	items, err := s.readRepository.GetPageForUser(currentUser.ID, query.Filter(), cursor, limit+1)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notifications for user", api.ErrInternal)
+		return
+	}
 
-		api.SendDataJSON(w, r, http.StatusOK, response)
+	response := NotificationPage{Items: make([]models.Notification, 0, limit)}
+
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[limit-1]
+		response.NextCursor = models.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	response.Items = append(response.Items, items...)
+
+	if err = s.render(r, currentUser.ID, response.Items); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't render notifications", api.ErrInternal)
 		return
 	}
 
 	api.SendDataJSON(w, r, http.StatusOK, response)
 }
 
+// Пересобрать тексты уведомлений на языке пользователя
+func (s *NotificationController) render(r *http.Request, userId string, items []models.Notification) error {
+	if s.Renderer == nil {
+		return nil
+	}
+
+	// Язык запроса важнее языка из настроек, например после смены языка в приложении
+	var locale string
+	if r.Header.Get("Accept-Language") != "" {
+		locale = i18n.Negotiate(r.Header.Get("Accept-Language"))
+	}
+
+	return s.Renderer.RenderForUser(userId, locale, items)
+}
+
+func (s *NotificationController) getUnreadCount(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	unread, err := s.readRepository.GetUnreadCountForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get unread notifications count", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, NotificationsUnreadResponse{Unread: unread})
+}
+
+func (s *NotificationController) markRead(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var form NotificationReadForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	_, err = form.Validate()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	updated, err := s.repository.MarkRead(currentUser.ID, form.IDs)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't mark notifications as read", api.ErrInternal)
+		return
+	}
+
+	s.sendUpdated(w, r, currentUser.ID, updated)
+}
+
+func (s *NotificationController) markOneRead(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !govalidator.IsUUID(id) {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong id"), "wrong format notification id", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	updated, err := s.repository.MarkRead(currentUser.ID, []string{id})
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't mark notification as read", api.ErrInternal)
+		return
+	}
+
+	s.sendUpdated(w, r, currentUser.ID, updated)
+}
+
+func (s *NotificationController) markAllRead(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	updated, err := s.repository.MarkAllRead(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't mark notifications as read", api.ErrInternal)
+		return
+	}
+
+	s.sendUpdated(w, r, currentUser.ID, updated)
+}
+
+func (s *NotificationController) deleteNotification(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if !govalidator.IsUUID(id) {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong id"), "wrong format notification id", api.ErrValidationData)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	updated, err := s.repository.Delete(currentUser.ID, id)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't delete notification", api.ErrInternal)
+		return
+	}
+
+	if updated == 0 {
+		api.SendErrorJSON(w, r, http.StatusNotFound, errors.New("notification not found"), "notification not found", api.ErrNoPermission)
+		return
+	}
+
+	s.sendUpdated(w, r, currentUser.ID, updated)
+}
+
+// Ответ с количеством измененных и оставшихся непрочитанных уведомлений, чтобы клиент обновил счетчик
+func (s *NotificationController) sendUpdated(w http.ResponseWriter, r *http.Request, userId string, updated int64) {
+	unread, err := s.readRepository.GetUnreadCountForUser(userId)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get unread notifications count", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, NotificationsUpdatedResponse{Updated: updated, Unread: unread})
+}
+
 func (s *NotificationController) notificationPush(w http.ResponseWriter, r *http.Request) {
 	if s.PushChannel == nil {
 		api.SendErrorJSON(w, r, http.StatusInternalServerError, nil, "PushChannel is nil", api.ErrInternal)
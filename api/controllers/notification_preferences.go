@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+	"strconv"
+	"time"
+)
+
+type NotificationPreferencesController struct {
+	authService    *auth.Service
+	dataService    store.DataService
+	repository     repositories.NotificationPreferencesRepository
+	readRepository readModels.NotificationPreferencesReadRepository
+}
+
+func NewNotificationPreferencesController(
+	authService *auth.Service,
+	dataService store.DataService,
+	repository repositories.NotificationPreferencesRepository,
+	readRepository readModels.NotificationPreferencesReadRepository) *NotificationPreferencesController {
+	return &NotificationPreferencesController{
+		authService:    authService,
+		dataService:    dataService,
+		repository:     repository,
+		readRepository: readRepository}
+}
+
+type NotificationPreferencesForm struct {
+	Types []models.NotificationPreference `json:"types"`
+}
+
+func (s *NotificationPreferencesForm) Validate() (bool, error) {
+	if len(s.Types) == 0 {
+		return false, errors.New("types is empty")
+	}
+
+	for i := range s.Types {
+		if _, err := s.Types[i].Validate(); err != nil {
+			return false, errors.New(err.Error() + ": " + strconv.Itoa(int(s.Types[i].Type)))
+		}
+	}
+
+	return true, nil
+}
+
+type ListMuteForm struct {
+	Until models.NullInt64 `json:"until"` // Пустое значение - без ограничения по времени
+}
+
+func (s *ListMuteForm) Validate() (bool, error) {
+	if s.Until.Valid && s.Until.Int64 <= time.Now().UTC().Unix() {
+		return false, errors.New("until must be in the future")
+	}
+
+	return true, nil
+}
+
+type NotificationPreferencesResponse struct {
+	Types      []models.NotificationPreference `json:"types"`
+	MutedLists []models.NotificationListMute   `json:"muted_lists"`
+}
+
+// Routes returns slice of server routes
+func (s *NotificationPreferencesController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "NotificationPreferences",
+			Method: "GET",
+			Path:   "/notifications/preferences",
+			Func:   s.getPreferences,
+		},
+		{
+			Name:   "NotificationPreferencesSave",
+			Method: "PUT",
+			Path:   "/notifications/preferences",
+			Func:   s.savePreferences,
+		},
+		{
+			Name:   "NotificationSettings",
+			Method: "GET",
+			Path:   "/notifications/settings",
+			Func:   s.getSettings,
+		},
+		{
+			Name:   "NotificationSettingsSave",
+			Method: "PUT",
+			Path:   "/notifications/settings",
+			Func:   s.saveSettings,
+		},
+		{
+			Name:   "ListMute",
+			Method: "PUT",
+			Path:   "/lists/{list_id}/mute",
+			Func:   s.muteList,
+		},
+		{
+			Name:   "ListUnmute",
+			Method: "DELETE",
+			Path:   "/lists/{list_id}/mute",
+			Func:   s.unmuteList,
+		},
+	}
+}
+
+func (s *NotificationPreferencesController) getPreferences(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	s.sendPreferences(w, r, currentUser.ID)
+}
+
+func (s *NotificationPreferencesController) savePreferences(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var form NotificationPreferencesForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	_, err = form.Validate()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	for i := range form.Types {
+		if err := s.repository.Save(currentUser.ID, &form.Types[i]); err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save notification preferences", api.ErrInternal)
+			return
+		}
+	}
+
+	s.sendPreferences(w, r, currentUser.ID)
+}
+
+func (s *NotificationPreferencesController) getSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	settings, err := s.readRepository.GetSettingsForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notification settings", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, settings)
+}
+
+func (s *NotificationPreferencesController) saveSettings(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	// Запрос накладывается на текущие настройки: поля, которые клиент не передал (например, locale у старых версий),
+	// не сбрасываются
+	settings, err := s.readRepository.GetSettingsForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notification settings", api.ErrInternal)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	if _, err = settings.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	if err = s.repository.SaveSettings(currentUser.ID, &settings); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save notification settings", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, settings)
+}
+
+func (s *NotificationPreferencesController) muteList(w http.ResponseWriter, r *http.Request) {
+	currentUser, listId, ok := s.getAccessibleList(w, r)
+	if !ok {
+		return
+	}
+
+	var form ListMuteForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	if _, err := form.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	mute := models.NotificationListMute{ListID: listId, MutedUntil: form.Until}
+	if err := s.repository.SaveListMute(currentUser.ID, &mute); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't mute list", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, mute)
+}
+
+func (s *NotificationPreferencesController) unmuteList(w http.ResponseWriter, r *http.Request) {
+	currentUser, listId, ok := s.getAccessibleList(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repository.DeleteListMute(currentUser.ID, listId); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't unmute list", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+// Проверить, что список доступен пользователю
+func (s *NotificationPreferencesController) getAccessibleList(w http.ResponseWriter, r *http.Request) (*models.User, string, bool) {
+	listId := mux.Vars(r)["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return nil, "", false
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return nil, "", false
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	if _, err := listsReadRepository.GetListAccessibleForUser(listId, currentUser.ID); err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return nil, "", false
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return nil, "", false
+	}
+
+	return currentUser, listId, true
+}
+
+func (s *NotificationPreferencesController) sendPreferences(w http.ResponseWriter, r *http.Request, userId string) {
+	preferences, err := s.readRepository.GetForUser(userId)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get notification preferences", api.ErrInternal)
+		return
+	}
+
+	mutes, err := s.readRepository.GetListMutesForUser(userId)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get muted lists", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, NotificationPreferencesResponse{Types: preferences, MutedLists: mutes})
+}
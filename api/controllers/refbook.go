@@ -1,19 +1,32 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"shopingList/api"
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/refbook"
 	"shopingList/pkg/repositories"
+	"strconv"
+	"strings"
 )
 
 type RefbookController struct {
 	categoriesRepository repositories.RefbookCategoriesRepository
 	productsRepository   repositories.RefbookProductsRepository
+	translator           *refbook.Translator
+	ProductIndex         *refbook.Index
 }
 
 func NewRefbookController(categoriesRepository repositories.RefbookCategoriesRepository,
-	productsRepository repositories.RefbookProductsRepository) *RefbookController {
-	return &RefbookController{categoriesRepository: categoriesRepository, productsRepository: productsRepository}
+	productsRepository repositories.RefbookProductsRepository,
+	translator *refbook.Translator) *RefbookController {
+	return &RefbookController{
+		categoriesRepository: categoriesRepository,
+		productsRepository:   productsRepository,
+		translator:           translator}
 }
 
 func (s *RefbookController) Routes() []api.Route {
@@ -24,25 +37,147 @@ func (s *RefbookController) Routes() []api.Route {
 			Path:   "/refbook",
 			Func:   s.getRefbook,
 		},
+		{
+			Name:   "MatchRefbookProduct",
+			Method: "GET",
+			Path:   "/refbook/match",
+			Func:   s.matchProduct,
+		},
 	}
 }
 
+// Вернуть справочник целиком или изменения с даты since (включая удаленные записи).
+// Названия переводятся на язык из Accept-Language, при отсутствии перевода остается название на языке по умолчанию.
+// Версия справочника передается в ETag, при совпадении с If-None-Match возвращается 304.
+// В ответе version - дата последнего изменения, ее клиент передает в since при следующем запросе.
+// Записи, измененные в ту же секунду, возвращаются повторно, поэтому клиент должен применять их идемпотентно
 func (s *RefbookController) getRefbook(w http.ResponseWriter, r *http.Request) {
-	categories, err := s.categoriesRepository.GetAll()
+	var since int64
+	if rawSince := r.URL.Query().Get("since"); rawSince != "" {
+		var err error
+		since, err = strconv.ParseInt(rawSince, 10, 64)
+		if err != nil || since < 0 {
+			api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong since"), "wrong since", api.ErrValidationData)
+			return
+		}
+	}
+
+	categoriesState, err := s.categoriesRepository.GetState()
 	if err != nil {
 		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can`t get categories", api.ErrInternal)
 		return
 	}
 
-	products, err := s.productsRepository.GetAll()
+	productsState, err := s.productsRepository.GetState()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get products", api.ErrInternal)
+		return
+	}
+
+	version := categoriesState.LastUpdatedAt
+	if productsState.LastUpdatedAt > version {
+		version = productsState.LastUpdatedAt
+	}
+
+	locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+	etag := fmt.Sprintf(`"%d-%d-%d-%s"`, version, categoriesState.Count, productsState.Count, locale)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Content-Language", locale)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var categories []models.RefbookCategory
+	var products *[]models.RefbookProduct
+
+	if since > 0 {
+		categories, err = s.categoriesRepository.GetUpdatedSince(since)
+	} else {
+		categories, err = s.categoriesRepository.GetAll()
+	}
+
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can`t get categories", api.ErrInternal)
+		return
+	}
+
+	if since > 0 {
+		products, err = s.productsRepository.GetUpdatedSince(since)
+	} else {
+		products, err = s.productsRepository.GetAll()
+	}
+
 	if err != nil {
 		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get products", api.ErrInternal)
 		return
 	}
 
+	if err = s.translator.TranslateCategories(locale, categories); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get translations", api.ErrInternal)
+		return
+	}
+
+	if products != nil {
+		if err = s.translator.TranslateProducts(locale, *products); err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get translations", api.ErrInternal)
+			return
+		}
+	}
+
 	data := make(map[string]interface{})
 	data["categories"] = categories
 	data["products"] = products
+	data["locale"] = locale
+	data["version"] = version
+	data["is_full"] = since == 0
 
 	api.SendDataJSON(w, r, http.StatusOK, data)
 }
+
+// Сопоставить название товара со справочником так же, как при синхронизации
+func (s *RefbookController) matchProduct(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("name is empty"), "wrong name", api.ErrValidationData)
+		return
+	}
+
+	if s.ProductIndex == nil {
+		api.SendDataJSON(w, r, http.StatusOK, nil)
+		return
+	}
+
+	matcher, err := s.ProductIndex.Matcher()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get products", api.ErrInternal)
+		return
+	}
+
+	match, ok := matcher.Match(name)
+	if !ok {
+		api.SendDataJSON(w, r, http.StatusOK, nil)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, match)
+}
+
+func etagMatches(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+		if value == "*" || value == etag {
+			return true
+		}
+	}
+
+	return false
+}
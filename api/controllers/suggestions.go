@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/suggestions"
+	"shopingList/store"
+	"strconv"
+	"time"
+)
+
+const maxSuggestionsLimit = 100
+
+type SuggestionsController struct {
+	authService               *auth.Service
+	dataService               store.DataService
+	suggestionsReadRepository readModels.SuggestionsReadRepository
+}
+
+func NewSuggestionsController(
+	authService *auth.Service,
+	dataService store.DataService,
+	suggestionsReadRepository readModels.SuggestionsReadRepository) *SuggestionsController {
+	return &SuggestionsController{
+		authService:               authService,
+		dataService:               dataService,
+		suggestionsReadRepository: suggestionsReadRepository}
+}
+
+func (s *SuggestionsController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "ListSuggestions",
+			Method: "GET",
+			Path:   "/lists/{list_id}/suggestions",
+			Func:   s.getSuggestions,
+		},
+	}
+}
+
+func (s *SuggestionsController) getSuggestions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	limit := suggestions.DefaultSuggestions
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 || limit > maxSuggestionsLimit {
+			api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong limit", api.ErrValidationData)
+			return
+		}
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListAccessibleForUser(listId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	items, err := itemsReadRepository.GetItemsForList(list.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get items", api.ErrInternal)
+		return
+	}
+
+	var listItems []models.ListItem
+	if items != nil {
+		listItems = *items
+	}
+
+	stats, err := s.suggestionsReadRepository.GetStatsForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get purchase stats", api.ErrInternal)
+		return
+	}
+
+	pairs, err := s.suggestionsReadRepository.GetPairsForUser(currentUser.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get purchase pairs", api.ErrInternal)
+		return
+	}
+
+	userProductsReadRepository := s.dataService.UserProductsReadRepository()
+
+	favorites, err := userProductsReadRepository.GetUpdatedUserProductsForUser(currentUser.ID, 0)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get favorites", api.ErrInternal)
+			return
+		}
+	}
+
+	result := suggestions.Rank(time.Now().UTC().Unix(), stats, pairs, favorites, listItems, limit)
+
+	api.SendDataJSON(w, r, http.StatusOK, result)
+}
@@ -16,6 +16,7 @@ type ShoppingListUpdates struct {
 	Items        []models.ListItem    `json:"items"`
 	Shares       []models.ListShare   `json:"shares"`
 	UserProducts []models.UserProduct `json:"user_products"`
+	Stores       []models.Store       `json:"stores"`
 }
 
 func (s *ShoppingListUpdates) Validate() (bool, []string) {
@@ -56,6 +57,13 @@ func (s *ShoppingListUpdates) Validate() (bool, []string) {
 		}
 	}
 
+	for _, store := range s.Stores {
+		_, err := store.Validate()
+		if err != nil {
+			errs = append(errs, "error in store "+store.ID+"; "+err.Error())
+		}
+	}
+
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -10,6 +10,7 @@ import (
 	"shopingList/api/auth"
 	"shopingList/api/controllers"
 	"shopingList/pkg/events"
+	"shopingList/pkg/refbook"
 	"shopingList/pkg/sync"
 	"shopingList/store"
 )
@@ -19,6 +20,7 @@ type SyncController struct {
 	dataService     store.DataService
 	chanGoodsChange chan events.GoodsChangeEvent
 	chanShareChange chan events.ShareListEvent
+	ProductIndex    *refbook.Index
 }
 
 func NewSyncController(
@@ -98,7 +100,8 @@ func (s *SyncController) saveSyncUpdates(w http.ResponseWriter, r *http.Request)
 	syncUpdater := sync.NewUpdater(s.dataService, *currentUser)
 	syncUpdater.ChanGoodsChange = s.chanGoodsChange
 	syncUpdater.ChanShareChange = s.chanShareChange
-	err = syncUpdater.RunUpdate(data.Users, data.Lists, data.Shares, data.Items, data.UserProducts)
+	syncUpdater.ProductIndex = s.ProductIndex
+	err = syncUpdater.RunUpdate(data.Users, data.Lists, data.Shares, data.Items, data.UserProducts, data.Stores)
 
 	if err != nil {
 		log.Errorln(errors.Wrap(err, "Error in saveSyncUpdates()"))
@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/asaskevich/govalidator"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/sync"
+	"shopingList/store"
+	"time"
+)
+
+type TemplatesController struct {
+	authService               *auth.Service
+	dataService               store.DataService
+	recurrencesRepository     repositories.ListRecurrencesRepository
+	recurrencesReadRepository readModels.ListRecurrencesReadRepository
+
+	// Часовой пояс владельца для часа запуска. Если не задан, час считается по UTC
+	Settings *readModels.NotificationPreferencesReadRepository
+}
+
+func NewTemplatesController(
+	authService *auth.Service,
+	dataService store.DataService,
+	recurrencesRepository repositories.ListRecurrencesRepository,
+	recurrencesReadRepository readModels.ListRecurrencesReadRepository) *TemplatesController {
+	return &TemplatesController{
+		authService:               authService,
+		dataService:               dataService,
+		recurrencesRepository:     recurrencesRepository,
+		recurrencesReadRepository: recurrencesReadRepository}
+}
+
+type TemplateInstantiateForm struct {
+	ListID           string `json:"list_id" valid:"uuid"`
+	Name             string `json:"name" valid:"stringlength(1|100)"`
+	SkipExisting     bool   `json:"skip_existing"`
+	MergeQuantities  bool   `json:"merge_quantities"`
+	ShareWithMembers bool   `json:"share_with_members"`
+}
+
+func (s *TemplateInstantiateForm) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type TemplateRecurrenceForm struct {
+	Type         string `json:"type"`
+	IntervalDays int    `json:"interval_days"`
+	WeekDay      int    `json:"week_day"`
+	MonthDay     int    `json:"month_day"`
+	Hour         int    `json:"hour"`
+}
+
+func (s *TemplatesController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "TemplateInstantiate",
+			Method: "POST",
+			Path:   "/templates/{template_id}/instantiate",
+			Func:   s.instantiate,
+		},
+		{
+			Name:   "TemplateRecurrence",
+			Method: "GET",
+			Path:   "/templates/{template_id}/recurrence",
+			Func:   s.getRecurrence,
+		},
+		{
+			Name:   "TemplateRecurrenceSave",
+			Method: "PUT",
+			Path:   "/templates/{template_id}/recurrence",
+			Func:   s.saveRecurrence,
+		},
+		{
+			Name:   "TemplateRecurrenceDelete",
+			Method: "DELETE",
+			Path:   "/templates/{template_id}/recurrence",
+			Func:   s.deleteRecurrence,
+		},
+	}
+}
+
+func (s *TemplatesController) instantiate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	templateId := vars["template_id"]
+
+	err := validation.Validate(templateId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format template id", api.ErrDecode)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var form TemplateInstantiateForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	if _, err := form.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	cloner := sync.NewTemplateCloner(s.dataService, *currentUser)
+	pack, err := cloner.Clone(templateId, sync.TemplateCloneOptions{
+		ListID:           form.ListID,
+		Name:             form.Name,
+		SkipExisting:     form.SkipExisting,
+		MergeQuantities:  form.MergeQuantities,
+		ShareWithMembers: form.ShareWithMembers,
+	})
+
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "template not found", api.ErrNoPermission)
+			return
+		}
+
+		if err == sync.ErrListIsNotTemplate {
+			api.SendErrorJSON(w, r, http.StatusBadRequest, err, "list is not a template", api.ErrValidationData)
+			return
+		}
+
+		if _, ok := err.(repositories.ErrDuplicate); ok {
+			api.SendErrorJSON(w, r, http.StatusConflict, err, "list with this id already exists", api.ErrValidationData)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't create list from template", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, pack)
+}
+
+func (s *TemplatesController) getRecurrence(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.getOwnTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	recurrence, err := s.recurrencesReadRepository.GetForList(template.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendDataJSON(w, r, http.StatusOK, nil)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get recurrence", api.ErrInternal)
+		return
+	}
+
+	if recurrence.IsDeleted {
+		api.SendDataJSON(w, r, http.StatusOK, nil)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, recurrence)
+}
+
+func (s *TemplatesController) saveRecurrence(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.getOwnTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	var form TemplateRecurrenceForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	now := time.Now().UTC()
+
+	recurrence, err := s.recurrencesReadRepository.GetForList(template.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get recurrence", api.ErrInternal)
+			return
+		}
+
+		recurrence = models.ListRecurrence{ListID: template.ID, OwnerID: template.OwnerID, CreatedAt: now.Unix()}
+	}
+
+	recurrence.Type = form.Type
+	recurrence.IntervalDays = form.IntervalDays
+	recurrence.WeekDay = form.WeekDay
+	recurrence.MonthDay = form.MonthDay
+	recurrence.Hour = form.Hour
+	recurrence.UpdatedAt = now.Unix()
+	recurrence.IsDeleted = false
+
+	if _, err := recurrence.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	location := time.UTC
+	if s.Settings != nil {
+		if location, err = s.Settings.GetLocationForUser(template.OwnerID); err != nil {
+			api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get owner timezone", api.ErrInternal)
+			return
+		}
+	}
+
+	// Расписание изменилось, отсчет начинается заново
+	recurrence.NextRunAt = 0
+	recurrence.NextRunAt = recurrence.NextRunAfter(now, location).Unix()
+
+	if err := s.recurrencesRepository.Save(&recurrence); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't save recurrence", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, recurrence)
+}
+
+func (s *TemplatesController) deleteRecurrence(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.getOwnTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	recurrence, err := s.recurrencesReadRepository.GetForList(template.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "recurrence not found", api.ErrValidationData)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get recurrence", api.ErrInternal)
+		return
+	}
+
+	recurrence.IsDeleted = true
+	recurrence.UpdatedAt = time.Now().UTC().Unix()
+
+	if err := s.recurrencesRepository.Save(&recurrence); err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't delete recurrence", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+// Вернуть шаблон текущего пользователя из параметров запроса.
+// Расписание может менять только владелец шаблона
+func (s *TemplatesController) getOwnTemplate(w http.ResponseWriter, r *http.Request) (*models.List, bool) {
+	vars := mux.Vars(r)
+	templateId := vars["template_id"]
+
+	err := validation.Validate(templateId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format template id", api.ErrDecode)
+		return nil, false
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return nil, false
+	}
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	template, err := listsReadRepository.GetListForIdAndOwner(templateId, currentUser.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "template not found", api.ErrNoPermission)
+			return nil, false
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get template", api.ErrInternal)
+		return nil, false
+	}
+
+	if template.IsDeleted || !template.IsTemplate {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, sync.ErrListIsNotTemplate, "list is not a template", api.ErrValidationData)
+		return nil, false
+	}
+
+	return &template, true
+}
@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/asaskevich/govalidator"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/schema"
+	"io"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services"
+	"shopingList/store"
+	"time"
+)
+
+type TripsController struct {
+	authService             *auth.Service
+	dataService             store.DataService
+	tripService             *services.TripService
+	tripsReadRepository     readModels.TripsReadRepository
+	purchasesReadRepository readModels.PurchasesReadRepository
+}
+
+func NewTripsController(
+	authService *auth.Service,
+	dataService store.DataService,
+	tripService *services.TripService,
+	tripsReadRepository readModels.TripsReadRepository,
+	purchasesReadRepository readModels.PurchasesReadRepository) *TripsController {
+	return &TripsController{
+		authService:             authService,
+		dataService:             dataService,
+		tripService:             tripService,
+		tripsReadRepository:     tripsReadRepository,
+		purchasesReadRepository: purchasesReadRepository}
+}
+
+type TripStartForm struct {
+	ID      string `json:"id" valid:"uuid"`
+	StoreID string `json:"store_id" valid:"uuid"`
+}
+
+func (s *TripStartForm) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type TripFinishForm struct {
+	Mode string `json:"mode" valid:"in(keep|clear|archive)"`
+}
+
+func (s *TripFinishForm) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type TripFinishResponse struct {
+	Trip      models.Trip       `json:"trip"`
+	Purchases []models.Purchase `json:"purchases"`
+}
+
+// Фильтр истории покупок. Если период не указан, то берется текущий месяц по UTC
+type PurchasesRequest struct {
+	From   int64  `schema:"from"`
+	To     int64  `schema:"to"`
+	ListID string `schema:"list_id"`
+}
+
+func (s *TripsController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "TripStart",
+			Method: "POST",
+			Path:   "/lists/{list_id}/trips",
+			Func:   s.start,
+		},
+		{
+			Name:   "Trip",
+			Method: "GET",
+			Path:   "/trips/{trip_id}",
+			Func:   s.getTrip,
+		},
+		{
+			Name:   "TripFinish",
+			Method: "POST",
+			Path:   "/trips/{trip_id}/finish",
+			Func:   s.finish,
+		},
+		{
+			Name:   "TripCancel",
+			Method: "POST",
+			Path:   "/trips/{trip_id}/cancel",
+			Func:   s.cancel,
+		},
+		{
+			Name:   "Purchases",
+			Method: "GET",
+			Path:   "/purchases",
+			Func:   s.getPurchases,
+		},
+	}
+}
+
+func (s *TripsController) start(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	listId := vars["list_id"]
+
+	err := validation.Validate(listId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	// Тело запроса необязательное
+	var form TripStartForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil && err != io.EOF {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	if _, err := form.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	list, ok := s.getAccessibleList(w, r, listId, currentUser.ID)
+	if !ok {
+		return
+	}
+
+	trip, err := s.tripService.Start(*currentUser, *list, form.ID, form.StoreID)
+	if err != nil {
+		if err == services.ErrTripAlreadyActive {
+			api.SendErrorJSON(w, r, http.StatusConflict, err, "list already has an active trip", api.ErrValidationData)
+			return
+		}
+
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "store not found", api.ErrNoPermission)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't start trip", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, trip)
+}
+
+func (s *TripsController) getTrip(w http.ResponseWriter, r *http.Request) {
+	trip, ok := s.getAccessibleTrip(w, r)
+	if !ok {
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, trip)
+}
+
+func (s *TripsController) finish(w http.ResponseWriter, r *http.Request) {
+	trip, ok := s.getAccessibleTrip(w, r)
+	if !ok {
+		return
+	}
+
+	var form TripFinishForm
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil && err != io.EOF {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't parse", api.ErrDecode)
+		return
+	}
+
+	if _, err := form.Validate(); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	if form.Mode == "" {
+		form.Mode = models.TripFinishKeep
+	}
+
+	purchases, err := s.tripService.Finish(*trip, form.Mode)
+	if err != nil {
+		if err == services.ErrTripIsNotActive {
+			api.SendErrorJSON(w, r, http.StatusConflict, err, "trip is not active", api.ErrValidationData)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't finish trip", api.ErrInternal)
+		return
+	}
+
+	finished, err := s.tripsReadRepository.GetTrip(trip.ID)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get trip", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, TripFinishResponse{Trip: finished, Purchases: purchases})
+}
+
+func (s *TripsController) cancel(w http.ResponseWriter, r *http.Request) {
+	trip, ok := s.getAccessibleTrip(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.tripService.Cancel(*trip); err != nil {
+		if err == services.ErrTripIsNotActive {
+			api.SendErrorJSON(w, r, http.StatusConflict, err, "trip is not active", api.ErrValidationData)
+			return
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't cancel trip", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+func (s *TripsController) getPurchases(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return
+	}
+
+	var query PurchasesRequest
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	if err = decoder.Decode(&query, r.URL.Query()); err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "can't query params", api.ErrDecode)
+		return
+	}
+
+	if query.ListID != "" && !govalidator.IsUUID(query.ListID) {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong list_id"), "wrong format list id", api.ErrDecode)
+		return
+	}
+
+	now := time.Now().UTC()
+	if query.From == 0 {
+		query.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	if query.To == 0 {
+		query.To = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+
+	if query.To <= query.From {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("wrong period"), "to must be greater than from", api.ErrValidationData)
+		return
+	}
+
+	purchases, err := s.purchasesReadRepository.GetForUser(currentUser.ID, query.ListID, query.From, query.To)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get purchases", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, purchases)
+}
+
+// Вернуть поход из параметров запроса, если список похода доступен текущему пользователю
+func (s *TripsController) getAccessibleTrip(w http.ResponseWriter, r *http.Request) (*models.Trip, bool) {
+	vars := mux.Vars(r)
+	tripId := vars["trip_id"]
+
+	err := validation.Validate(tripId, validation.Required, validation.Length(36, 36))
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "wrong format trip id", api.ErrDecode)
+		return nil, false
+	}
+
+	currentUser, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't auth user by token", api.ErrUserNotFound)
+		return nil, false
+	}
+
+	trip, err := s.tripsReadRepository.GetTrip(tripId)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "trip not found", api.ErrNoPermission)
+			return nil, false
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get trip", api.ErrInternal)
+		return nil, false
+	}
+
+	if _, ok := s.getAccessibleList(w, r, trip.ListID, currentUser.ID); !ok {
+		return nil, false
+	}
+
+	return &trip, true
+}
+
+func (s *TripsController) getAccessibleList(w http.ResponseWriter, r *http.Request, listId string, userId string) (*models.List, bool) {
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	list, err := listsReadRepository.GetListAccessibleForUser(listId, userId)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			api.SendErrorJSON(w, r, http.StatusNotFound, err, "list not found", api.ErrNoPermission)
+			return nil, false
+		}
+
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "can't get list", api.ErrInternal)
+		return nil, false
+	}
+
+	if list.IsDeleted {
+		api.SendErrorJSON(w, r, http.StatusNotFound, repositories.ErrNotFound{}, "list not found", api.ErrNoPermission)
+		return nil, false
+	}
+
+	return &list, true
+}
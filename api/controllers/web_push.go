@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"shopingList/api"
+	"shopingList/api/auth"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+// Подписки браузеров на Web Push. Работает рядом с FCMTokenController для мобильных устройств
+type WebPushController struct {
+	authService *auth.Service
+	repository  repositories.WebPushSubscriptionsRepository
+
+	// Открытый ключ VAPID для подписки в браузере. Пустой, если Web Push не настроен
+	PublicKey string
+}
+
+func NewWebPushController(authService *auth.Service, repository repositories.WebPushSubscriptionsRepository) *WebPushController {
+	return &WebPushController{authService: authService, repository: repository}
+}
+
+type WebPushKeyResponse struct {
+	PublicKey string `json:"public_key"`
+}
+
+func (s *WebPushController) getKey(w http.ResponseWriter, r *http.Request) {
+	if s.PublicKey == "" {
+		api.SendErrorJSON(w, r, http.StatusNotFound, errors.New("web push is not configured"), "web push is not configured", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, WebPushKeyResponse{PublicKey: s.PublicKey})
+}
+
+func (s *WebPushController) saveSubscription(w http.ResponseWriter, r *http.Request) {
+	user, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't authorized user", api.ErrUserNotFound)
+		return
+	}
+
+	subscription := models.WebPushSubscription{}
+
+	err = json.NewDecoder(r.Body).Decode(&subscription)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "error decode request", api.ErrValidationData)
+		return
+	}
+
+	_, err = subscription.Validate()
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "validation error", api.ErrValidationData)
+		return
+	}
+
+	err = s.repository.Save(user.ID, &subscription)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "error save subscription", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+func (s *WebPushController) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	user, err := GetAuthorizedUser(s.authService, r)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusUnauthorized, err, "can't authorized user", api.ErrUserNotFound)
+		return
+	}
+
+	subscription := models.WebPushSubscription{}
+
+	err = json.NewDecoder(r.Body).Decode(&subscription)
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, err, "error decode request", api.ErrValidationData)
+		return
+	}
+
+	if subscription.Endpoint == "" {
+		api.SendErrorJSON(w, r, http.StatusBadRequest, errors.New("endpoint is empty"), "validation error", api.ErrValidationData)
+		return
+	}
+
+	err = s.repository.Delete(user.ID, subscription.Endpoint)
+	if _, ok := err.(repositories.ErrNotFound); ok {
+		api.SendErrorJSON(w, r, http.StatusNotFound, err, "subscription not found", api.ErrValidationData)
+		return
+	}
+
+	if err != nil {
+		api.SendErrorJSON(w, r, http.StatusInternalServerError, err, "error delete subscription", api.ErrInternal)
+		return
+	}
+
+	api.SendDataJSON(w, r, http.StatusOK, nil)
+}
+
+func (s *WebPushController) Routes() []api.Route {
+	return []api.Route{
+		{
+			Name:   "WebPushKey",
+			Method: "GET",
+			Path:   "/webpush/key",
+			Func:   s.getKey,
+		},
+		{
+			Name:   "SaveWebPushSubscription",
+			Method: "POST",
+			Path:   "/webpush/subscription",
+			Func:   s.saveSubscription,
+		},
+		{
+			Name:   "DeleteWebPushSubscription",
+			Method: "DELETE",
+			Path:   "/webpush/subscription",
+			Func:   s.deleteSubscription,
+		},
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"shopingList/pkg/repositories"
+)
+
+var (
+	barcodesLimit int
+)
+
+// barcodesCmd represents the barcodes command
+var barcodesCmd = &cobra.Command{
+	Use:   "barcodes",
+	Short: "list barcodes submitted by users",
+	Long: `list barcodes that users bound to their own products because they are missing in the product catalog.
+Barcodes are sorted by the number of users. Output is csv: barcode;name;category_id;users`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listBarcodeSubmissions(barcodesLimit)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(barcodesCmd)
+	barcodesCmd.Flags().IntVarP(&barcodesLimit, "limit", "l", 100, "max count of barcodes")
+}
+
+func listBarcodeSubmissions(limit int) {
+	db, err := openDb(appConfig.Database)
+	if err != nil {
+		log.Fatal("Error open database")
+	}
+
+	submissionsRepository := repositories.NewBarcodeSubmissionsRepository(db)
+
+	stats, err := submissionsRepository.GetTop(limit)
+	if err != nil {
+		log.Fatalln("Error get barcode submissions", err)
+	}
+
+	fmt.Println("barcode;name;category_id;users")
+	for _, stat := range stats {
+		fmt.Printf("%s;%s;%d;%d\n", stat.Barcode, stat.Name, stat.CategoryID, stat.UsersCount)
+	}
+}
@@ -16,33 +16,41 @@ limitations under the License.
 package internal
 
 import (
-	"encoding/csv"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
-	"shopingList/pkg/models"
-	"shopingList/pkg/repositories"
-	"shopingList/pkg/services"
+	"path/filepath"
+	"shopingList/pkg/refbook"
 	"strings"
-	"unicode"
 )
 
 var (
-	csvFile string
+	csvFile      string
+	importFormat string
+	dryRun       bool
 )
 
 // importCmd represents the import command
 var importCmd = &cobra.Command{
 	Use:   "import",
-	Short: "import of product catalog from csv",
-	Long: `import of product catalog from csv-file. 
-  - Fields delimiter: ;'
-  - Count fields in the file: 2
-	
-  Fields:
-  - First column: Title of category of a product (string)
-  - Second column: Title of a product (string)`,
+	Short: "import of product catalog from csv or json",
+	Long: `import of product catalog from csv or json file.
+Existing categories and products are matched by normalized title (or by id) and keep their ids,
+missing ones are marked as deleted. The command prints the diff of changes.
+
+  CSV:
+  - Fields delimiter: ;
+  - The first line may be a header with column names: id, category, title, synonyms, barcodes, unit,
+    and translations title_<locale>, category_<locale> (kk, en)
+  - Without a header the columns are: category;title;synonyms;barcodes;unit (only the first two are required)
+  - Several synonyms or barcodes are separated by |
+
+  JSON:
+  - Array of objects: {"id": 1, "category": "...", "title": "...", "synonyms": [], "barcodes": [], "unit": "kg",
+    "titles": {"en": "..."}, "category_titles": {"en": "..."}}
+
+  Main titles are in the default language (ru), translations fall back to them.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("import of product catalog from the file: " + csvFile)
 		importFile(csvFile)
@@ -51,13 +59,37 @@ var importCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(importCmd)
-	importCmd.Flags().StringVarP(&csvFile, "file", "f", "", "csv-file (required)")
+	importCmd.Flags().StringVarP(&csvFile, "file", "f", "", "csv or json file (required)")
+	importCmd.Flags().StringVar(&importFormat, "format", "", "file format: csv or json (by default by file extension)")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the diff without saving changes")
 	importCmd.MarkFlagRequired("file")
 }
 
 func importFile(filename string) {
 	log.Infoln("Starting import refbook")
 
+	rows, err := readImportFile(filename)
+	if err != nil {
+		log.Fatalln("Error reading file", err)
+	}
+
+	if len(rows) == 0 {
+		log.Fatalln("array of products is empty")
+	}
+
+	for i := range rows {
+		rows[i].CategoryTitle = refbook.NormalizeTitleCase(rows[i].CategoryTitle)
+		rows[i].Title = refbook.NormalizeTitleCase(rows[i].Title)
+
+		for locale, title := range rows[i].Titles {
+			rows[i].Titles[locale] = refbook.NormalizeTitleCase(title)
+		}
+
+		for locale, title := range rows[i].CategoryTitles {
+			rows[i].CategoryTitles[locale] = refbook.NormalizeTitleCase(title)
+		}
+	}
+
 	db, err := openDb(appConfig.Database)
 	if err != nil {
 		log.Fatal("Error open database")
@@ -70,84 +102,76 @@ func importFile(filename string) {
 
 	defer tx.Rollback()
 
-	categoriesRepository := repositories.NewRefbookCategoriesRepository(tx)
-	productsRepository := repositories.NewRefbookProductsRepository(tx)
-	service := services.NewRefbookImportService(categoriesRepository, productsRepository)
+	importer := refbook.NewImporter(tx)
 
-	file, err := os.Open(filename)
+	// Записи не пересоздаются, поэтому id, на которые ссылаются товары пользователей, сохраняются
+	diff, err := importer.Import(rows)
 	if err != nil {
-		log.Fatalln("Error open file", err)
+		log.Fatalln("Error import refbook", err)
 	}
-	defer file.Close()
 
-	var productsForms []services.ProductImportForm
-	categories := make(map[string]models.RefbookCategory)
-	categoriesIds := make(map[string]int64)
+	printImportDiff(diff)
 
-	reader := csv.NewReader(file)
-	reader.Comma = ';'
-	reader.FieldsPerRecord = 2
-	record, err := reader.ReadAll()
-	if err != nil {
-		log.Fatalln("Error reading file", err)
+	if dryRun {
+		log.Infoln("Dry run, changes are not saved")
+		return
 	}
 
-	for row := range record {
-		categoryName := capitalize(strings.ToLower(record[row][0]))
-		productName := capitalize(strings.ToLower(record[row][1]))
-
-		_, ok := categoriesIds[categoryName]
-		if !ok {
-			categories[categoryName] = models.RefbookCategory{Title: categoryName}
-		}
-
-		productsForms = append(productsForms, ProductForm{Title: productName, CategoryTitle: categoryName})
+	if err = tx.Commit(); err != nil {
+		log.Fatalln("Error commit", err)
 	}
 
-	if len(productsForms) == 0 {
-		log.Fatalln("array of productsForms is empty")
-	}
+	log.Infoln("Import completed successfully")
+}
 
-	err = service.ImportCategories(categories)
+func readImportFile(filename string) ([]refbook.ImportRow, error) {
+	file, err := os.Open(filename)
 	if err != nil {
-		log.Fatalln("Error import categories", err)
+		return nil, err
 	}
+	defer file.Close()
 
-	for _, category := range categories {
-		categoriesIds[category.Title] = category.ID
+	format := importFormat
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
 	}
 
-	err = service.ImportProducts(productsForms, categoriesIds)
-	if err != nil {
-		log.Fatalln("Error import categories", err)
+	switch format {
+	case "json":
+		return refbook.ReadImportJSON(file)
+	case "csv", "":
+		return refbook.ReadImportCSV(file)
+	default:
+		return nil, fmt.Errorf("unknown format %s", format)
 	}
-
-	tx.Commit()
-
-	log.Infoln("Import completed successfully")
-	log.Infof("imported categories: %v", len(categories))
-	log.Infof("imported products: %v", len(productsForms))
 }
 
-func capitalize(str string) string {
-	if len(str) == 0 {
-		return ""
+func printImportDiff(diff refbook.ImportDiff) {
+	for _, change := range diff.Changes {
+		switch change.Action {
+		case refbook.ImportActionRename:
+			fmt.Printf("%-7s %-8s #%d %q -> %q\n", change.Action, change.Entity, change.ID, change.OldTitle, change.Title)
+		case refbook.ImportActionMove:
+			fmt.Printf("%-7s %-8s #%d %q: %q -> %q\n", change.Action, change.Entity, change.ID, change.Title,
+				change.OldCategory, change.Category)
+		default:
+			if change.Category != "" {
+				fmt.Printf("%-7s %-8s #%d %q (%s)\n", change.Action, change.Entity, change.ID, change.Title, change.Category)
+			} else {
+				fmt.Printf("%-7s %-8s #%d %q\n", change.Action, change.Entity, change.ID, change.Title)
+			}
+		}
 	}
 
-	tmp := []rune(str)
-	tmp[0] = unicode.ToUpper(tmp[0])
-	return string(tmp)
-}
-
-type ProductForm struct {
-	Title         string
-	CategoryTitle string
-}
+	actions := []string{refbook.ImportActionAdd, refbook.ImportActionRename, refbook.ImportActionMove,
+		refbook.ImportActionUpdate, refbook.ImportActionRestore, refbook.ImportActionRemove}
 
-func (s ProductForm) GetTitle() string {
-	return s.Title
-}
+	for _, entity := range []string{refbook.ImportEntityCategory, refbook.ImportEntityProduct} {
+		var counts []string
+		for _, action := range actions {
+			counts = append(counts, fmt.Sprintf("%s: %d", action, diff.Count(entity, action)))
+		}
 
-func (s ProductForm) GetCategoryTitle() string {
-	return s.CategoryTitle
+		log.Infof("%s changes: %s", entity, strings.Join(counts, ", "))
+	}
 }
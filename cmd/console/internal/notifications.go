@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/scheduler"
+)
+
+var (
+	notificationsRetentionDays int
+)
+
+// notificationsCmd represents the notifications command
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "maintain notifications",
+}
+
+var notificationsCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "delete notifications older than retention period",
+	Long: `delete notifications older than retention period. By default the period is taken from
+notifications.retentionDays in config, the application does the same in background every hour`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cleanupNotifications(notificationsRetentionDays)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notificationsCmd)
+	notificationsCmd.AddCommand(notificationsCleanupCmd)
+	notificationsCleanupCmd.Flags().IntVarP(&notificationsRetentionDays, "days", "d", 0, "retention period in days")
+}
+
+func cleanupNotifications(days int) {
+	db, err := openDb(appConfig.Database)
+	if err != nil {
+		log.Fatal("Error open database")
+	}
+
+	config := appConfig.Notifications
+	if days > 0 {
+		config = models.NotificationsConfig{RetentionDays: days}
+	}
+
+	cleaner := scheduler.NewNotificationsCleaner(
+		repositories.NewNotificationsRepository(db), config.Retention(), scheduler.SystemClock{}, 0)
+
+	deleted, err := cleaner.Clean()
+	if err != nil {
+		log.Fatalln("Error delete old notifications", err)
+	}
+
+	fmt.Printf("deleted: %d\n", deleted)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"strconv"
+	"time"
+)
+
+const pushRetryBatch = 1000
+
+var (
+	pushDeadLimit int
+	pushRetryAll  bool
+)
+
+// pushCmd represents the push command
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "inspect push queue",
+}
+
+var pushStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "show push queue size",
+	Run: func(cmd *cobra.Command, args []string) {
+		showPushStats()
+	},
+}
+
+var pushDeadCmd = &cobra.Command{
+	Use:   "dead",
+	Short: "list failed push jobs",
+	Long: `list push jobs that exhausted their attempts, newest first.
+Output is csv: id;target_user_id;type;attempts;failed_at;last_error`,
+	Run: func(cmd *cobra.Command, args []string) {
+		listDeadPushJobs(pushDeadLimit)
+	},
+}
+
+var pushRetryCmd = &cobra.Command{
+	Use:   "retry [ids]",
+	Short: "return failed push jobs to the queue",
+	Long:  `return failed push jobs with given ids to the queue, or all failed jobs with --all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		retryDeadPushJobs(args, pushRetryAll)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+	pushCmd.AddCommand(pushStatsCmd)
+	pushCmd.AddCommand(pushDeadCmd)
+	pushCmd.AddCommand(pushRetryCmd)
+	pushDeadCmd.Flags().IntVarP(&pushDeadLimit, "limit", "l", 100, "max count of jobs")
+	pushRetryCmd.Flags().BoolVarP(&pushRetryAll, "all", "a", false, "retry all failed jobs")
+}
+
+func showPushStats() {
+	db, err := openDb(appConfig.Database)
+	if err != nil {
+		log.Fatal("Error open database")
+	}
+
+	readRepository := readModels.NewPushJobsReadRepository(db)
+
+	stats, err := readRepository.GetStats()
+	if err != nil {
+		log.Fatalln("Error get push queue stats", err)
+	}
+
+	fmt.Printf("pending: %d\nretrying: %d\ndead: %d\n", stats.Pending, stats.Retrying, stats.Dead)
+}
+
+func listDeadPushJobs(limit int) {
+	db, err := openDb(appConfig.Database)
+	if err != nil {
+		log.Fatal("Error open database")
+	}
+
+	readRepository := readModels.NewPushJobsReadRepository(db)
+
+	jobs, err := readRepository.GetDeadLetters(limit)
+	if err != nil {
+		log.Fatalln("Error get failed push jobs", err)
+	}
+
+	fmt.Println("id;target_user_id;type;attempts;failed_at;last_error")
+	for _, job := range jobs {
+		fmt.Printf("%d;%s;%s;%d;%s;%s\n", job.ID, job.TargetUserId, job.TypeNotification, job.Attempts,
+			time.Unix(job.FailedAt.Int64, 0).UTC().Format(time.RFC3339), job.LastError)
+	}
+}
+
+func retryDeadPushJobs(args []string, all bool) {
+	if len(args) == 0 && !all {
+		log.Fatal("Pass job ids or --all")
+	}
+
+	db, err := openDb(appConfig.Database)
+	if err != nil {
+		log.Fatal("Error open database")
+	}
+
+	repository := repositories.NewPushJobsRepository(db)
+	readRepository := readModels.NewPushJobsReadRepository(db)
+
+	retried := 0
+	retry := func(id int64) bool {
+		if err := repository.RetryDeadLetter(id); err != nil {
+			log.Errorf("Error retry push job %d; %s", id, err)
+			return false
+		}
+		retried++
+		return true
+	}
+
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			log.Fatalf("Wrong job id %s", arg)
+		}
+		retry(id)
+	}
+
+	// Возвращенные задания пропадают из dead letter, поэтому берем пачки, пока они есть
+	for all {
+		jobs, err := readRepository.GetDeadLetters(pushRetryBatch)
+		if err != nil {
+			log.Fatalln("Error get failed push jobs", err)
+		}
+
+		progress := false
+		for _, job := range jobs {
+			if retry(job.ID) {
+				progress = true
+			}
+		}
+
+		if len(jobs) < pushRetryBatch || !progress {
+			break
+		}
+	}
+
+	fmt.Printf("retried: %d\n", retried)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package internal
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"shopingList/pkg/services/push"
+)
+
+// webPushKeysCmd represents the webpush-keys command
+var webPushKeysCmd = &cobra.Command{
+	Use:   "webpush-keys",
+	Short: "generate VAPID keys for web push",
+	Long: `generate a new VAPID key pair. Put the private key to push.webPush.privateKey in config,
+the public key is served to browsers by GET /webpush/key`,
+	Run: func(cmd *cobra.Command, args []string) {
+		generateWebPushKeys()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(webPushKeysCmd)
+}
+
+func generateWebPushKeys() {
+	private, public, err := push.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatalln("Error generate VAPID keys", err)
+	}
+
+	fmt.Printf("private: %s\npublic: %s\n", private, public)
+}
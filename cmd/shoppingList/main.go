@@ -18,12 +18,18 @@ import (
 	"shopingList/pkg/events"
 	"shopingList/pkg/listeners"
 	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
 	"shopingList/pkg/readModels"
+	"shopingList/pkg/refbook"
 	"shopingList/pkg/repositories"
+	"shopingList/pkg/scheduler"
 	"shopingList/pkg/services"
+	"shopingList/pkg/services/email"
 	"shopingList/pkg/services/login_limiter"
+	"shopingList/pkg/services/push"
 	"shopingList/pkg/services/sms"
 	"shopingList/store/mysql"
+	"time"
 )
 
 var (
@@ -65,15 +71,47 @@ func main() {
 	}
 
 	tokenStorage := mysql.NewFCMTokenStorage(db)
-	pushChannel := make(chan services.PushNotificationMessage)
 
-	if config.HasFirebaseCredentials() {
-		pushService, err := services.NewPushService(config.FirebaseCredentialsFile, tokenStorage, false)
+	// Без провайдеров канал не создается, listeners в этом случае push не отправляют
+	var pushChannel chan services.PushNotificationMessage
+
+	if config.HasPushProviders() {
+		// Провайдер выбирается по платформе токена: FCM, APNs или sink для разработки
+		pushProvider, err := push.NewProviderFromConfig(config)
 		if err != nil {
-			log.Fatalln("[ERROR]: can't start pushService: ", err)
+			log.Fatalln("[ERROR]: can't start push provider: ", err)
+		}
+
+		// Push проходит через проверку тихих часов получателей и сразу ставится в очередь в базе,
+		// отправка идет из очереди с повторами при ошибках. Буфер канала не дает listeners ждать запись в базу
+		pushChannel = make(chan services.PushNotificationMessage, 100)
+
+		deviceTokens := &push.DeviceTokens{
+			FCM:                   tokenStorage,
+			WebPushRepository:     repositories.NewWebPushSubscriptionsRepository(db),
+			WebPushReadRepository: readModels.NewWebPushSubscriptionsReadRepository(db),
 		}
 
-		go pushService.ListenChannel(pushChannel)
+		pushDispatcher := scheduler.NewPushDispatcher(
+			repositories.NewPushJobsRepository(db), deviceTokens, pushProvider, config.PushQueue,
+			scheduler.SystemClock{}, 5*time.Second)
+		go pushDispatcher.Run(applicationStopped)
+
+		quietHoursListener := listeners.QuietHoursListener{
+			Settings: readModels.NewNotificationPreferencesReadRepository(db),
+			Deferred: repositories.NewDeferredPushesRepository(db),
+			Queue:    &listeners.PushQueueListener{Repository: repositories.NewPushJobsRepository(db)},
+			Clock:    scheduler.SystemClock{},
+		}
+		go quietHoursListener.Run(pushChannel)
+
+		deferredPushesReleaser := scheduler.NewDeferredPushesReleaser(
+			repositories.NewDeferredPushesRepository(db), readModels.NewDeferredPushesReadRepository(db),
+			scheduler.SystemClock{}, time.Minute)
+		deferredPushesReleaser.PushChannel = pushChannel
+		go deferredPushesReleaser.Run(applicationStopped)
+	} else if config.HasPushSettings() {
+		log.Errorln("[ERROR]: push settings are set, but no push provider is configured, push notifications are disabled")
 	}
 
 	restServer := api.New(authenticator, dataService, config.Server.Port, applicationStopped)
@@ -81,14 +119,78 @@ func main() {
 	// Репозиторий для уведомлений
 	notificationRepository := repositories.NewNotificationsRepository(db)
 	notificationReadRepository := readModels.NewNotificationsReadRepository(db)
+	notificationPreferencesRepository := repositories.NewNotificationPreferencesRepository(db)
+	notificationPreferencesReadRepository := readModels.NewNotificationPreferencesReadRepository(db)
+	recipientsFilter := &listeners.RecipientsFilter{Repository: notificationPreferencesReadRepository}
+	notificationRenderer := &notifications.Renderer{
+		Settings: notificationPreferencesReadRepository,
+		Aliases:  readModels.NewContactAliasesReadRepository(db),
+	}
+
+	// Письма отправляются, только если настроен SMTP
+	var emailNotifier *notifications.EmailNotifier
+	var mailer email.Mailer
+
+	if config.HasSmtp() {
+		smtpMailer, err := email.NewSMTPMailer(config.Smtp)
+		if err != nil {
+			log.Fatalln("[ERROR]: can't start mailer: ", err)
+		}
+		mailer = smtpMailer
+
+		emailChannel := make(chan email.Message, 100)
+		emailListener := listeners.EmailListener{Mailer: mailer}
+		go emailListener.Run(emailChannel)
+
+		emailNotifier = &notifications.EmailNotifier{
+			Preferences: notificationPreferencesReadRepository,
+			Channel:     emailChannel,
+		}
+	}
 
 	// Channels для listeners
+	budgetsRepository := repositories.NewBudgetsRepository(db)
+	budgetsReadRepository := readModels.NewBudgetsReadRepository(db)
+	spendingReadRepository := readModels.NewSpendingReadRepository(db)
+
+	// Проверка бюджетов идет в фоне, буфер сглаживает всплески синхронизаций
+	chanBudgetCheck := make(chan events.GoodsChangeEvent, 100)
+	budgetListener := listeners.BudgetListener{
+		Repository:             notificationRepository,
+		BudgetsRepository:      budgetsRepository,
+		BudgetsReadRepository:  budgetsReadRepository,
+		SpendingReadRepository: spendingReadRepository,
+		SharesReadRepository:   readModels.NewSharesReadRepository(db),
+		PushChannel:            pushChannel,
+		Recipients:             recipientsFilter,
+		Renderer:               notificationRenderer,
+		Emails:                 emailNotifier,
+	}
+	go budgetListener.Run(chanBudgetCheck)
+
+	// Уведомления по товарам откладываются и объединяются
+	pendingNotificationsRepository := repositories.NewPendingNotificationsRepository(db)
+
 	chanGoodsChange := make(chan events.GoodsChangeEvent)
-	goodChangeListener := listeners.GoodChangeListener{Repository: notificationRepository, PushChannel: pushChannel}
+	goodChangeListener := listeners.GoodChangeListener{
+		Repository:    notificationRepository,
+		PushChannel:   pushChannel,
+		BudgetChannel: chanBudgetCheck,
+		Recipients:    recipientsFilter,
+		Renderer:      notificationRenderer,
+		Emails:        emailNotifier,
+		Pending:       &pendingNotificationsRepository,
+	}
 	go goodChangeListener.Run(chanGoodsChange)
 
 	chanShareChange := make(chan events.ShareListEvent)
-	shareChangeListener := listeners.ShareListChangeListener{Repository: notificationRepository, PushChannel: pushChannel}
+	shareChangeListener := listeners.ShareListChangeListener{
+		Repository:  notificationRepository,
+		PushChannel: pushChannel,
+		Recipients:  recipientsFilter,
+		Renderer:    notificationRenderer,
+		Emails:      emailNotifier,
+	}
 	go shareChangeListener.Run(chanShareChange)
 
 	// Публичные контроллеры
@@ -103,27 +205,85 @@ func main() {
 
 	// Контроллеры под авторизацией
 	privateController := controllers.NewPrivate(dataService)
+	productIndex := refbook.NewIndex(
+		repositories.NewRefbookProductsRepository(db),
+		repositories.NewRefbookSynonymsRepository(db),
+		10*time.Minute)
 	syncController := sync.NewSyncController(authenticator, dataService, chanGoodsChange, chanShareChange)
+	syncController.ProductIndex = productIndex
 	tokenController := controllers.NewFCMTokenController(authenticator, tokenStorage)
+	webPushController := controllers.NewWebPushController(
+		authenticator, repositories.NewWebPushSubscriptionsRepository(db))
+
+	if config.Push.WebPush.PrivateKey != "" {
+		vapidKey, err := push.ParseVAPIDPrivateKey(config.Push.WebPush.PrivateKey)
+		if err != nil {
+			log.Fatalln("[ERROR]: wrong VAPID key: ", err)
+		}
+		webPushController.PublicKey = push.VAPIDPublicKey(vapidKey)
+	}
 	sharedListController := controllers.NewSharedListsController(authenticator, dataService)
 	sharedListController.ChanShareChange = chanShareChange
+	recurrencesRepository := repositories.NewListRecurrencesRepository(db)
+	recurrencesReadRepository := readModels.NewListRecurrencesReadRepository(db)
+	templatesController := controllers.NewTemplatesController(
+		authenticator, dataService, recurrencesRepository, recurrencesReadRepository)
+	templatesController.Settings = &notificationPreferencesReadRepository
+	listsController := controllers.NewListsController(
+		authenticator, dataService, spendingReadRepository)
+	budgetsController := controllers.NewBudgetsController(
+		authenticator, dataService, budgetsRepository, budgetsReadRepository)
+	tripsReadRepository := readModels.NewTripsReadRepository(db)
+	tripsController := controllers.NewTripsController(
+		authenticator, dataService, services.NewTripService(dataService, tripsReadRepository),
+		tripsReadRepository, readModels.NewPurchasesReadRepository(db))
+	suggestionsController := controllers.NewSuggestionsController(
+		authenticator, dataService, readModels.NewSuggestionsReadRepository(db))
+	autocompleteController := controllers.NewAutocompleteController(authenticator, dataService, productIndex)
+	refbookTranslator := refbook.NewTranslator(
+		repositories.NewRefbookCategoryTranslationsRepository(db),
+		repositories.NewRefbookProductTranslationsRepository(db))
 	refbookController := controllers.NewRefbookController(
 		repositories.NewRefbookCategoriesRepository(db),
-		repositories.NewRefbookProductsRepository(db))
+		repositories.NewRefbookProductsRepository(db),
+		refbookTranslator)
+	barcodesController := controllers.NewBarcodesController(
+		authenticator, dataService,
+		repositories.NewRefbookCategoriesRepository(db),
+		repositories.NewRefbookProductsRepository(db),
+		repositories.NewRefbookBarcodesRepository(db),
+		repositories.NewBarcodeSubmissionsRepository(db),
+		refbookTranslator)
+	refbookController.ProductIndex = productIndex
+	listsController.ProductIndex = productIndex
 
 	notificationController := controllers.NewNotificationController(
 		authenticator, notificationRepository, notificationReadRepository)
+	notificationController.Renderer = notificationRenderer
+	contactsController := controllers.NewContactsController(
+		authenticator, repositories.NewContactAliasesRepository(db), readModels.NewContactAliasesReadRepository(db))
 
-	if config.HasFirebaseCredentials() {
-		notificationController.PushChannel = pushChannel
-	}
+	notificationPreferencesController := controllers.NewNotificationPreferencesController(
+		authenticator, dataService, notificationPreferencesRepository, notificationPreferencesReadRepository)
+
+	notificationController.PushChannel = pushChannel
 
 	restServer.AddPrivateRoutes(privateController.Routes()...)
 	restServer.AddPrivateRoutes(syncController.Routes()...)
 	restServer.AddPrivateRoutes(notificationController.Routes()...)
+	restServer.AddPrivateRoutes(notificationPreferencesController.Routes()...)
+	restServer.AddPrivateRoutes(contactsController.Routes()...)
 	restServer.AddPrivateRoutes(tokenController.Routes()...)
+	restServer.AddPrivateRoutes(webPushController.Routes()...)
 	restServer.AddPrivateRoutes(refbookController.Routes()...)
+	restServer.AddPrivateRoutes(barcodesController.Routes()...)
 	restServer.AddPrivateRoutes(sharedListController.Routes()...)
+	restServer.AddPrivateRoutes(templatesController.Routes()...)
+	restServer.AddPrivateRoutes(listsController.Routes()...)
+	restServer.AddPrivateRoutes(budgetsController.Routes()...)
+	restServer.AddPrivateRoutes(tripsController.Routes()...)
+	restServer.AddPrivateRoutes(suggestionsController.Routes()...)
+	restServer.AddPrivateRoutes(autocompleteController.Routes()...)
 
 	tgListener, err := pkg.CreateTgListener(config.TelegramBotToken, db)
 	if err != nil {
@@ -132,6 +292,39 @@ func main() {
 		go tgListener.Run()
 	}
 
+	// Создание списков из шаблонов по расписанию
+	recurringListsScheduler := scheduler.NewRecurringListsScheduler(
+		dataService, recurrencesRepository, recurrencesReadRepository, scheduler.SystemClock{}, time.Minute)
+	recurringListsScheduler.ChanShareChange = chanShareChange
+	recurringListsScheduler.Settings = &notificationPreferencesReadRepository
+	go recurringListsScheduler.Run(applicationStopped)
+
+	// Пересчет статистики покупок для подсказок
+	suggestionsBuilder := scheduler.NewSuggestionsBuilder(
+		dataService, readModels.NewPurchasesReadRepository(db), scheduler.SystemClock{}, 6*time.Hour)
+	go suggestionsBuilder.Run(applicationStopped)
+
+	// Объединенные уведомления по товарам и ежедневный дайджест
+	notificationsFlusher := scheduler.NewNotificationsFlusher(
+		dataService, readModels.NewPendingNotificationsReadRepository(db), scheduler.SystemClock{}, 30*time.Second)
+	notificationsFlusher.PushChannel = pushChannel
+	notificationsFlusher.Renderer = notificationRenderer
+	notificationsFlusher.Emails = emailNotifier
+	go notificationsFlusher.Run(applicationStopped)
+
+	// Удаление уведомлений старше срока хранения
+	notificationsCleaner := scheduler.NewNotificationsCleaner(
+		notificationRepository, config.Notifications.Retention(), scheduler.SystemClock{}, time.Hour)
+	go notificationsCleaner.Run(applicationStopped)
+
+	// Еженедельная сводка по спискам на email
+	if mailer != nil {
+		weeklySummarySender := scheduler.NewWeeklySummarySender(
+			notificationPreferencesRepository, notificationPreferencesReadRepository,
+			readModels.NewListActivityReadRepository(db), mailer, scheduler.SystemClock{}, 15*time.Minute)
+		go weeklySummarySender.Run(applicationStopped)
+	}
+
 	go restServer.Run()
 
 	go func() {
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_list_recurrences`
+(
+    `list_id`       varchar(36) NOT NULL,
+    `owner_id`      varchar(36) NOT NULL,
+    `type`          varchar(10) NOT NULL,
+    `interval_days` SMALLINT    NOT NULL DEFAULT 0,
+    `week_day`      TINYINT     NOT NULL DEFAULT 0,
+    `month_day`     TINYINT     NOT NULL DEFAULT 0,
+    `hour`          TINYINT     NOT NULL DEFAULT 0,
+    `next_run_at`   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_run_at`   TIMESTAMP   NULL     DEFAULT NULL,
+    `created_at`    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `is_deleted`    tinyint(1)  NOT NULL DEFAULT '0',
+    PRIMARY KEY (`list_id`),
+    KEY `next_run_at` (`is_deleted`, `next_run_at`),
+    CONSTRAINT `sl_list_recurrences_sl_item_list_id_fk`
+        FOREIGN KEY (`list_id`) REFERENCES `sl_item_list` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_list_recurrences`;
+-- +goose StatementEnd
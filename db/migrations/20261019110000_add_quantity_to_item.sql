@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    ADD `quantity` DECIMAL(12, 3) NULL DEFAULT NULL AFTER `value`,
+    ADD `unit`     VARCHAR(8)     NOT NULL DEFAULT '' AFTER `quantity`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    DROP `quantity`,
+    DROP `unit`;
+-- +goose StatementEnd
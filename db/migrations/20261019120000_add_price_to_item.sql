@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    ADD `price`    DECIMAL(12, 2) NULL DEFAULT NULL AFTER `unit`,
+    ADD `currency` CHAR(3)        NOT NULL DEFAULT '' AFTER `price`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    DROP `price`,
+    DROP `currency`;
+-- +goose StatementEnd
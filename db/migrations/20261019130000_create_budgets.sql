@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_budgets`
+(
+    `owner_id`    varchar(36)    NOT NULL,
+    `list_id`     varchar(36)    NOT NULL DEFAULT '',
+    `amount`      DECIMAL(12, 2) NOT NULL,
+    `currency`    CHAR(3)        NOT NULL,
+    `notified_at` TIMESTAMP      NULL     DEFAULT NULL,
+    `created_at`  TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`  TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `is_deleted`  tinyint(1)     NOT NULL DEFAULT '0',
+    PRIMARY KEY (`owner_id`, `list_id`),
+    KEY `list_id` (`list_id`, `is_deleted`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_budgets`;
+-- +goose StatementEnd
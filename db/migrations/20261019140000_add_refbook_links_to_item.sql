@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    ADD `product_id`       INT           NULL DEFAULT NULL AFTER `currency`,
+    ADD `category_id`      INT           NULL DEFAULT NULL AFTER `product_id`,
+    ADD `match_confidence` DECIMAL(4, 3) NULL DEFAULT NULL AFTER `category_id`,
+    ADD KEY `product_id` (`product_id`),
+    ADD KEY `category_id` (`category_id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_item`
+    DROP KEY `product_id`,
+    DROP KEY `category_id`,
+    DROP `product_id`,
+    DROP `category_id`,
+    DROP `match_confidence`;
+-- +goose StatementEnd
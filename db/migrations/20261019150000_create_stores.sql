@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_stores`
+(
+    `id`             varchar(36)  NOT NULL,
+    `owner_id`       varchar(36)  NOT NULL,
+    `name`           varchar(100) NOT NULL DEFAULT '',
+    `category_order` TEXT         NOT NULL,
+    `created_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `received_at`    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `is_deleted`     tinyint(1)   NOT NULL DEFAULT '0',
+    PRIMARY KEY (`id`),
+    KEY `owner_received` (`owner_id`, `received_at`),
+    CONSTRAINT `sl_stores_sl_users_id_fk`
+        FOREIGN KEY (`owner_id`) REFERENCES `sl_users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_stores`;
+-- +goose StatementEnd
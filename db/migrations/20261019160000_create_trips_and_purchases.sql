@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_trips`
+(
+    `id`          varchar(36) NOT NULL,
+    `list_id`     varchar(36) NOT NULL,
+    `user_id`     varchar(36) NOT NULL,
+    `store_id`    varchar(36) NULL     DEFAULT NULL,
+    `status`      varchar(10) NOT NULL,
+    `started_at`  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `finished_at` TIMESTAMP   NULL     DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    KEY `list_status` (`list_id`, `status`),
+    CONSTRAINT `sl_trips_sl_item_list_id_fk`
+        FOREIGN KEY (`list_id`) REFERENCES `sl_item_list` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_purchases`
+(
+    `id`              varchar(36)    NOT NULL,
+    `trip_id`         varchar(36)    NOT NULL,
+    `list_id`         varchar(36)    NOT NULL,
+    `item_id`         varchar(36)    NOT NULL,
+    `user_id`         varchar(36)    NOT NULL,
+    `store_id`        varchar(36)    NULL     DEFAULT NULL,
+    `name`            varchar(140)   NOT NULL DEFAULT '',
+    `quantity`        DECIMAL(12, 3) NULL     DEFAULT NULL,
+    `unit`            varchar(8)     NOT NULL DEFAULT '',
+    `price`           DECIMAL(12, 2) NULL     DEFAULT NULL,
+    `currency`        CHAR(3)        NOT NULL DEFAULT '',
+    `product_id`      INT            NULL     DEFAULT NULL,
+    `category_id`     INT            NULL     DEFAULT NULL,
+    `item_updated_at` TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `purchased_at`    TIMESTAMP      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `item_version` (`item_id`, `item_updated_at`),
+    KEY `list_purchased_at` (`list_id`, `purchased_at`),
+    KEY `user_purchased_at` (`user_id`, `purchased_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_purchases`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_trips`;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_suggestion_products`
+(
+    `user_id`         varchar(36)   NOT NULL,
+    `product_key`     varchar(160)  NOT NULL,
+    `name`            varchar(140)  NOT NULL DEFAULT '',
+    `product_id`      INT           NULL     DEFAULT NULL,
+    `category_id`     INT           NULL     DEFAULT NULL,
+    `purchases_count` INT           NOT NULL DEFAULT 0,
+    `interval_days`   DECIMAL(8, 2) NOT NULL DEFAULT 0,
+    `last_bought_at`  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `product_key`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_suggestion_pairs`
+(
+    `user_id`      varchar(36)   NOT NULL,
+    `product_key`  varchar(160)  NOT NULL,
+    `related_key`  varchar(160)  NOT NULL,
+    `related_name` varchar(140)  NOT NULL DEFAULT '',
+    `support`      INT           NOT NULL DEFAULT 0,
+    `confidence`   DECIMAL(5, 4) NOT NULL DEFAULT 0,
+    PRIMARY KEY (`user_id`, `product_key`, `related_key`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_suggestion_pairs`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_suggestion_products`;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_categories`
+    ADD `is_deleted` TINYINT(1) NOT NULL DEFAULT 0 AFTER `title`,
+    ADD KEY `updated_at` (`updated_at`);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_products`
+    ADD `is_deleted` TINYINT(1) NOT NULL DEFAULT 0 AFTER `category_id`,
+    ADD KEY `updated_at` (`updated_at`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_categories`
+    DROP KEY `updated_at`,
+    DROP `is_deleted`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_products`
+    DROP KEY `updated_at`,
+    DROP `is_deleted`;
+-- +goose StatementEnd
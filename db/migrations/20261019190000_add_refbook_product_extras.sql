@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_products`
+    ADD `default_unit` VARCHAR(10) NOT NULL DEFAULT '' AFTER `category_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_product_synonyms`
+(
+    `product_id` INT          NOT NULL,
+    `title`      VARCHAR(100) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL,
+    PRIMARY KEY (`product_id`, `title`),
+    KEY `title` (`title`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_product_barcodes`
+(
+    `barcode`    VARCHAR(14) NOT NULL,
+    `product_id` INT         NOT NULL,
+    PRIMARY KEY (`barcode`),
+    KEY `product_id` (`product_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_product_barcodes`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_product_synonyms`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_products`
+    DROP `default_unit`;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_category_translations`
+(
+    `category_id` INT          NOT NULL,
+    `locale`      VARCHAR(5)   NOT NULL,
+    `title`       VARCHAR(100) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL,
+    PRIMARY KEY (`category_id`, `locale`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_product_translations`
+(
+    `product_id` INT          NOT NULL,
+    `locale`     VARCHAR(5)   NOT NULL,
+    `title`      VARCHAR(100) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL,
+    PRIMARY KEY (`product_id`, `locale`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_product_translations`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_category_translations`;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_barcode_submissions`
+(
+    `barcode`         VARCHAR(14)  NOT NULL,
+    `user_id`         varchar(36)  NOT NULL,
+    `user_product_id` varchar(36)  NOT NULL,
+    `name`            VARCHAR(100) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL,
+    `category_id`     INT          NOT NULL,
+    `created_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`barcode`, `user_id`),
+    KEY `user_product_id` (`user_product_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_barcode_submissions`;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    ADD `read_at`    TIMESTAMP  NULL     DEFAULT NULL AFTER `target_user_id`,
+    ADD `is_deleted` TINYINT(1) NOT NULL DEFAULT 0 AFTER `read_at`,
+    ADD `updated_at` TIMESTAMP  NULL     DEFAULT NULL AFTER `created_at`,
+    ADD KEY `target_user_unread` (`target_user_id`, `is_deleted`, `read_at`),
+    ADD KEY `target_user_updated` (`target_user_id`, `updated_at`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    DROP KEY `target_user_unread`,
+    DROP KEY `target_user_updated`,
+    DROP `read_at`,
+    DROP `is_deleted`,
+    DROP `updated_at`;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_notification_preferences`
+(
+    `user_id`    varchar(36) NOT NULL,
+    `type`       SMALLINT    NOT NULL,
+    `channel`    VARCHAR(10) NOT NULL,
+    `updated_at` TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `type`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_notification_list_mutes`
+(
+    `user_id`     varchar(36) NOT NULL,
+    `list_id`     varchar(36) NOT NULL,
+    `muted_until` TIMESTAMP   NULL     DEFAULT NULL,
+    `created_at`  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `list_id`),
+    KEY `list_id` (`list_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_notification_list_mutes`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_notification_preferences`;
+-- +goose StatementEnd
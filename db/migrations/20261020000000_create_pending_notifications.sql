@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_pending_notifications`
+(
+    `id`             BIGINT       NOT NULL AUTO_INCREMENT,
+    `target_user_id` varchar(36)  NOT NULL,
+    `user_id`        varchar(36)  NOT NULL,
+    `user_name`      VARCHAR(100) NOT NULL DEFAULT '',
+    `user_phone`     BIGINT       NOT NULL DEFAULT 0,
+    `list_id`        varchar(36)  NOT NULL,
+    `list_name`      VARCHAR(255) NOT NULL DEFAULT '',
+    `item_id`        varchar(36)  NOT NULL,
+    `item_name`      VARCHAR(255) NOT NULL DEFAULT '',
+    `type`           SMALLINT     NOT NULL,
+    `push`           tinyint(1)   NOT NULL DEFAULT '1',
+    `is_digest`      tinyint(1)   NOT NULL DEFAULT '0',
+    `created_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `group` (`is_digest`, `target_user_id`, `user_id`, `list_id`),
+    KEY `created_at` (`created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_pending_notifications`;
+-- +goose StatementEnd
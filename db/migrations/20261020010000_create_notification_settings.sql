@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_notification_settings`
+(
+    `user_id`     varchar(36) NOT NULL,
+    `timezone`    VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    `quiet_start` SMALLINT    NULL     DEFAULT NULL,
+    `quiet_end`   SMALLINT    NULL     DEFAULT NULL,
+    `quiet_mode`  VARCHAR(10) NOT NULL DEFAULT 'defer',
+    `updated_at`  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_deferred_pushes`
+(
+    `id`             BIGINT       NOT NULL AUTO_INCREMENT,
+    `target_user_id` varchar(36)  NOT NULL,
+    `type`           SMALLINT     NOT NULL,
+    `message`        VARCHAR(512) NOT NULL,
+    `user_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `user_phone`     VARCHAR(50)  NOT NULL DEFAULT '',
+    `list_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `item_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `release_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `release_at` (`release_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_deferred_pushes`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_notification_settings`;
+-- +goose StatementEnd
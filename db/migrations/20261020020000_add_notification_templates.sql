@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    ADD `template` VARCHAR(64) NOT NULL DEFAULT '' AFTER `message`,
+    ADD `params`   TEXT        NULL     DEFAULT NULL AFTER `template`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    ADD `locale` VARCHAR(5) NOT NULL DEFAULT 'ru' AFTER `timezone`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_contact_aliases`
+(
+    `user_id`         varchar(36)  NOT NULL,
+    `contact_user_id` varchar(36)  NOT NULL,
+    `alias`           VARCHAR(100) NOT NULL,
+    `updated_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`user_id`, `contact_user_id`),
+    KEY `contact_user_id` (`contact_user_id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_contact_aliases`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    DROP `locale`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    DROP `template`,
+    DROP `params`;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_push_jobs`
+(
+    `id`              BIGINT       NOT NULL AUTO_INCREMENT,
+    `target_user_id`  varchar(36)  NOT NULL,
+    `type`            SMALLINT     NOT NULL,
+    `message`         VARCHAR(512) NOT NULL,
+    `user_id`         VARCHAR(36)  NOT NULL DEFAULT '',
+    `user_phone`      VARCHAR(50)  NOT NULL DEFAULT '',
+    `list_id`         VARCHAR(36)  NOT NULL DEFAULT '',
+    `item_id`         VARCHAR(36)  NOT NULL DEFAULT '',
+    `tokens`          TEXT         NULL     DEFAULT NULL,
+    `attempts`        INT          NOT NULL DEFAULT 0,
+    `last_error`      VARCHAR(512) NOT NULL DEFAULT '',
+    `next_attempt_at` TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `locked_until`    TIMESTAMP    NULL     DEFAULT NULL,
+    `lock_token`      varchar(36)  NULL     DEFAULT NULL,
+    `created_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `next_attempt_at` (`next_attempt_at`),
+    KEY `lock_token` (`lock_token`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE `sl_push_dead_letters`
+(
+    `id`             BIGINT       NOT NULL,
+    `target_user_id` varchar(36)  NOT NULL,
+    `type`           SMALLINT     NOT NULL,
+    `message`        VARCHAR(512) NOT NULL,
+    `user_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `user_phone`     VARCHAR(50)  NOT NULL DEFAULT '',
+    `list_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `item_id`        VARCHAR(36)  NOT NULL DEFAULT '',
+    `tokens`         TEXT         NULL     DEFAULT NULL,
+    `attempts`       INT          NOT NULL DEFAULT 0,
+    `last_error`     VARCHAR(512) NOT NULL DEFAULT '',
+    `created_at`     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `failed_at`      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `failed_at` (`failed_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_push_dead_letters`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE `sl_push_jobs`;
+-- +goose StatementEnd
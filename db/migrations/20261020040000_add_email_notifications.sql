@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_preferences`
+    ADD `email` TINYINT(1) NOT NULL DEFAULT 0 AFTER `channel`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    ADD `weekly_email`         TINYINT(1) NOT NULL DEFAULT 0 AFTER `quiet_mode`,
+    ADD `weekly_email_sent_at` TIMESTAMP  NULL     DEFAULT NULL AFTER `weekly_email`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    DROP `weekly_email_sent_at`,
+    DROP `weekly_email`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_preferences`
+    DROP `email`;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `sl_web_push_subscriptions`
+(
+    `id`            BIGINT        NOT NULL AUTO_INCREMENT,
+    `user_id`       varchar(36)   NOT NULL,
+    `endpoint`      VARCHAR(1024) NOT NULL,
+    `endpoint_hash` CHAR(64)      NOT NULL,
+    `p256dh`        VARCHAR(100)  NOT NULL,
+    `auth`          VARCHAR(32)   NOT NULL,
+    `created_at`    TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`    TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `endpoint_hash` (`endpoint_hash`),
+    KEY `user_id` (`user_id`),
+    CONSTRAINT `sl_web_push_subscriptions_sl_users_id_fk`
+        FOREIGN KEY (`user_id`) REFERENCES `sl_users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `sl_web_push_subscriptions`;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    DROP KEY `target_user_id`,
+    ADD KEY `target_user_created` (`target_user_id`, `created_at`, `id`),
+    ADD KEY `target_user_type_created` (`target_user_id`, `type`, `created_at`, `id`),
+    ADD KEY `target_user_list_created` (`target_user_id`, `list_id`, `created_at`, `id`),
+    ADD KEY `created_at` (`created_at`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_notifications`
+    DROP KEY `target_user_created`,
+    DROP KEY `target_user_type_created`,
+    DROP KEY `target_user_list_created`,
+    DROP KEY `created_at`,
+    ADD KEY `target_user_id` (`target_user_id`, `created_at`);
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+UPDATE `sl_trips` t
+    JOIN `sl_trips` newer ON newer.`list_id` = t.`list_id` AND newer.`status` = 'active'
+        AND (newer.`started_at` > t.`started_at` OR (newer.`started_at` = t.`started_at` AND newer.`id` > t.`id`))
+SET t.`status`      = 'cancelled',
+    t.`finished_at` = CURRENT_TIMESTAMP
+WHERE t.`status` = 'active';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_trips`
+    ADD `active_list_id` varchar(36) GENERATED ALWAYS AS (IF(`status` = 'active', `list_id`, NULL)) STORED,
+    ADD UNIQUE KEY `active_list_id` (`active_list_id`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_trips`
+    DROP KEY `active_list_id`,
+    DROP `active_list_id`;
+-- +goose StatementEnd
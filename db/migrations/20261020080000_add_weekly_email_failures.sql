@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    ADD `weekly_email_failures` TINYINT NOT NULL DEFAULT 0 AFTER `weekly_email_sent_at`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_notification_settings`
+    DROP `weekly_email_failures`;
+-- +goose StatementEnd
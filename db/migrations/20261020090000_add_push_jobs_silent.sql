@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `sl_push_jobs`
+    ADD `silent` TINYINT(1) NOT NULL DEFAULT 0 AFTER `item_id`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_push_dead_letters`
+    ADD `silent` TINYINT(1) NOT NULL DEFAULT 0 AFTER `item_id`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `sl_push_jobs`
+    DROP `silent`;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE `sl_push_dead_letters`
+    DROP `silent`;
+-- +goose StatementEnd
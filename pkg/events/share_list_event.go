@@ -11,6 +11,7 @@ const (
 	ShareListEventRefuse     = "refuse"
 	ShareListEventDelete     = "share-delete"
 	ShareListEventListDelete = "list-delete"
+	ShareListEventRecurring  = "recurring-create"
 )
 
 type ShareListEventType string
@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LocaleRu = "ru"
+	LocaleKk = "kk"
+	LocaleEn = "en"
+)
+
+// Язык по умолчанию, на нем заполнены основные названия
+const DefaultLocale = LocaleRu
+
+var Supported = []string{LocaleRu, LocaleKk, LocaleEn}
+
+func IsSupported(locale string) bool {
+	for _, l := range Supported {
+		if l == locale {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Привести "kk-KZ", "EN_us" и т.п. к поддерживаемому коду языка, либо вернуть пустую строку
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	if IsSupported(locale) {
+		return locale
+	}
+
+	return ""
+}
+
+// Выбрать язык по заголовку Accept-Language с учетом весов q.
+// Если ни один язык не поддерживается, возвращается язык по умолчанию
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					value = 0
+				}
+
+				q = value
+			}
+		}
+
+		locale := Normalize(fields[0])
+		if locale == "" || q <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, candidate{locale: locale, q: q})
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].locale
+}
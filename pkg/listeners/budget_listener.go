@@ -0,0 +1,188 @@
+package listeners
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/events"
+	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services"
+	"time"
+)
+
+// Проверка превышения бюджетов после изменения цен товаров.
+// Получает события изменения товаров от GoodChangeListener, чтобы не замедлять запрос синхронизации
+type BudgetListener struct {
+	Repository             repositories.NotificationsRepository
+	BudgetsRepository      repositories.BudgetsRepository
+	BudgetsReadRepository  readModels.BudgetsReadRepository
+	SpendingReadRepository readModels.SpendingReadRepository
+	SharesReadRepository   readModels.SharesReadRepository
+	PushChannel            chan services.PushNotificationMessage
+
+	// Настройки уведомлений получателей. Если не задан, уведомляются все
+	Recipients *RecipientsFilter
+
+	// Тексты на языке получателей. Если не задан, используется язык по умолчанию
+	Renderer *notifications.Renderer
+
+	// Письма подписанным на email. Если не задан, письма не отправляются
+	Emails *notifications.EmailNotifier
+}
+
+func (s *BudgetListener) Run(channel chan events.GoodsChangeEvent) {
+	for event := range channel {
+		log.Info("Receive message for BudgetListener")
+
+		err := s.Handle(&event)
+		if err != nil {
+			log.Error("Error handle event", err)
+		}
+	}
+}
+
+func (s *BudgetListener) Handle(event interface{}) error {
+	model, ok := event.(*events.GoodsChangeEvent)
+
+	if !ok {
+		err := errors.New(fmt.Sprintf("event must be a type not GoodsChangeEvent, event is type: %#v", event))
+		log.Fatalf("%+v", err)
+	}
+
+	if err := s.checkListBudget(model); err != nil {
+		return err
+	}
+
+	return s.checkMonthlyBudget(model)
+}
+
+// Бюджет списка сравнивается с суммой цен всех товаров списка.
+// Уведомление отправляется один раз при превышении и снова после возврата в рамки бюджета
+func (s *BudgetListener) checkListBudget(model *events.GoodsChangeEvent) error {
+	item := model.Item()
+
+	budget, err := s.BudgetsReadRepository.GetActiveForList(item.ListID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			return nil
+		}
+
+		return errors.Wrap(err, "Error get list budget")
+	}
+
+	totals, err := s.SpendingReadRepository.GetListTotals(item.ListID)
+	if err != nil {
+		return errors.Wrap(err, "Error get list totals")
+	}
+
+	var planned float64
+	for _, total := range totals {
+		if total.Currency == budget.Currency {
+			planned = total.Planned
+		}
+	}
+
+	if planned <= budget.Amount {
+		if budget.NotifiedAt != 0 {
+			return s.BudgetsRepository.SetNotifiedAt(budget.OwnerID, budget.ListID, 0)
+		}
+
+		return nil
+	}
+
+	if budget.NotifiedAt != 0 {
+		return nil
+	}
+
+	targetIds, err := s.SharesReadRepository.GetAcceptedUserIdsFromSharedList(budget.ListID, budget.OwnerID)
+	if err != nil {
+		return errors.Wrap(err, "Error get list members")
+	}
+
+	targetIds = append(targetIds, budget.OwnerID)
+
+	params := models.NotificationParams{
+		List: model.ListName(), Spent: planned, Budget: budget.Amount, Currency: budget.Currency}
+
+	if err = s.notify(model, notifications.TemplateBudgetList, params, targetIds); err != nil {
+		return err
+	}
+
+	return s.BudgetsRepository.SetNotifiedAt(budget.OwnerID, budget.ListID, time.Now().UTC().Unix())
+}
+
+// Месячный бюджет сравнивается с суммой товаров, отмеченных пользователем в текущем месяце.
+// Уведомление отправляется не чаще раза в месяц
+func (s *BudgetListener) checkMonthlyBudget(model *events.GoodsChangeEvent) error {
+	item := model.Item()
+
+	if !item.IsMarked || item.UserMarked.IsEmpty() || !item.Price.Valid {
+		return nil
+	}
+
+	userId := item.UserMarked.String
+
+	budget, err := s.BudgetsReadRepository.Get(userId, "")
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			return nil
+		}
+
+		return errors.Wrap(err, "Error get monthly budget")
+	}
+
+	if budget.IsDeleted || budget.Currency != item.Currency {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if budget.NotifiedAt >= monthStart.Unix() {
+		return nil
+	}
+
+	spent, err := s.SpendingReadRepository.GetMarkedByUserTotal(
+		userId, budget.Currency, monthStart.Unix(), monthStart.AddDate(0, 1, 0).Unix())
+	if err != nil {
+		return errors.Wrap(err, "Error get monthly spend")
+	}
+
+	if spent <= budget.Amount {
+		return nil
+	}
+
+	params := models.NotificationParams{Spent: spent, Budget: budget.Amount, Currency: budget.Currency}
+
+	if err = s.notify(model, notifications.TemplateBudgetMonth, params, []string{userId}); err != nil {
+		return err
+	}
+
+	return s.BudgetsRepository.SetNotifiedAt(budget.OwnerID, budget.ListID, now.Unix())
+}
+
+func (s *BudgetListener) notify(model *events.GoodsChangeEvent, template string, params models.NotificationParams, targetIds []string) error {
+	user := model.User()
+	params.ActorName = user.Name
+
+	form := models.NotificationCreateForm{
+		TypeNotification: models.NotificationTypeBudgetExceeded,
+		Template:         template,
+		Params:           params,
+		UserId:           user.ID,
+		UserPhone:        user.Phone,
+		ListId:           model.Item().ListID,
+		ItemId:           models.NullString{String: model.Item().ID, Valid: true},
+	}
+
+	inAppIds, pushIds := s.Recipients.Split(form.TypeNotification, form.ListId, targetIds)
+
+	if err := deliver(&s.Repository, s.Renderer, s.PushChannel, s.Emails, form, inAppIds, pushIds); err != nil {
+		return errors.Wrap(err, "Error create budget notification")
+	}
+
+	return nil
+}
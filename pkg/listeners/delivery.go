@@ -0,0 +1,63 @@
+package listeners
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services"
+)
+
+// Создать уведомления с текстом на языке каждого получателя, отправить push и письма подписанным на email.
+// Получатели push с одинаковым текстом объединяются в одно сообщение
+func deliver(
+	repository *repositories.NotificationsRepository,
+	renderer *notifications.Renderer,
+	pushChannel chan services.PushNotificationMessage,
+	emails *notifications.EmailNotifier,
+	form models.NotificationCreateForm,
+	inAppIds []string,
+	pushIds []string) error {
+	notifications.Fill(&form)
+	messages := renderer.Messages(form, inAppIds)
+
+	for _, targetUserId := range inAppIds {
+		form.TargetUserId = targetUserId
+		form.Message = messages[targetUserId]
+
+		if err := repository.Create(&form); err != nil {
+			return err
+		}
+	}
+
+	emails.Notify(form.TypeNotification, inAppIds, messages)
+
+	if len(pushIds) == 0 {
+		return nil
+	}
+
+	if pushChannel == nil {
+		log.Println("[ERROR] PushChannel is nil")
+		return nil
+	}
+
+	var texts []string
+	targets := make(map[string][]string)
+
+	for _, targetUserId := range pushIds {
+		text := messages[targetUserId]
+		if _, ok := targets[text]; !ok {
+			texts = append(texts, text)
+		}
+
+		targets[text] = append(targets[text], targetUserId)
+	}
+
+	for _, text := range texts {
+		form.TargetUserId = ""
+		form.Message = text
+		pushChannel <- services.PushNotificationMessage{Notification: form, TargetUserIds: targets[text]}
+	}
+
+	return nil
+}
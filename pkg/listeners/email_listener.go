@@ -0,0 +1,19 @@
+package listeners
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/services/email"
+)
+
+// Отправка писем из канала, чтобы SMTP не задерживал обработку событий
+type EmailListener struct {
+	Mailer email.Mailer
+}
+
+func (s *EmailListener) Run(channel chan email.Message) {
+	for message := range channel {
+		if err := s.Mailer.Send(message); err != nil {
+			log.Errorln("Error send email to " + message.To + "; " + err.Error())
+		}
+	}
+}
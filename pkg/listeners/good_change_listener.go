@@ -1,10 +1,10 @@
 package listeners
 
 import (
-	"fmt"
 	log "github.com/sirupsen/logrus"
 	"shopingList/pkg/events"
 	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
 	"shopingList/pkg/repositories"
 	"shopingList/pkg/services"
 )
@@ -12,6 +12,22 @@ import (
 type GoodChangeListener struct {
 	Repository  repositories.NotificationsRepository
 	PushChannel chan services.PushNotificationMessage
+
+	// Канал для проверки бюджетов. Должен быть буферизованным: отправка блокирующая, чтобы проверка не терялась,
+	// а буфер не дает медленной проверке бюджета задерживать обработку событий
+	BudgetChannel chan events.GoodsChangeEvent
+
+	// Настройки уведомлений получателей. Если не задан, уведомляются все
+	Recipients *RecipientsFilter
+
+	// Тексты на языке получателей. Если не задан, используется язык по умолчанию
+	Renderer *notifications.Renderer
+
+	// Письма подписанным на email. Если не задан, письма не отправляются
+	Emails *notifications.EmailNotifier
+
+	// Отложенные события. Если задан, уведомления объединяются и создаются в NotificationsFlusher
+	Pending *repositories.PendingNotificationsRepository
 }
 
 func (s *GoodChangeListener) Run(channel chan events.GoodsChangeEvent) {
@@ -31,52 +47,92 @@ func (s *GoodChangeListener) Handle(event interface{}) error {
 		log.Fatalf("event must be a type not GoodsChangeEvent, event is type: %#v/n", event)
 	}
 
-	var message string
+	var err error
+	if s.Pending != nil {
+		err = s.postpone(model)
+	} else {
+		err = s.notify(model)
+	}
 
-	typeEvent := int(model.TypeNotification())
+	if err != nil {
+		return err
+	}
+
+	// Проверяются все изменения: удаление товара или цены тоже меняет сумму и может вернуть список в рамки бюджета
+	if s.BudgetChannel != nil {
+		s.BudgetChannel <- *model
+	}
+
+	return nil
+}
+
+// Создать уведомления и отправить push сразу
+func (s *GoodChangeListener) notify(model *events.GoodsChangeEvent) error {
 	item := model.Item()
 	user := model.User()
-	listName := model.ListName()
-
-	switch typeEvent {
-	case models.NotificationTypeGoodsCreate:
-		message = fmt.Sprintf("Пользователь %d добавил товар \"%s\" в список \"%s\"", user.Phone, item.Name, listName)
-	case models.NotificationTypeGoodsChange:
-		message = fmt.Sprintf("Пользователь %d изменил товар \"%s\" из списка \"%s\"", user.Phone, item.Name, listName)
-	case models.NotificationTypeGoodsCheck:
-		message = fmt.Sprintf("Пользователь %d отметил товар \"%s\" из списка \"%s\"", user.Phone, item.Name, listName)
-	case models.NotificationTypeGoodsUncheck:
-		message = fmt.Sprintf("Пользователь %d снял отметку с товара \"%s\" из списка \"%s\"", user.Phone, item.Name, listName)
-	case models.NotificationTypeGoodsDelete:
-		message = fmt.Sprintf("Пользователь %d удалил товар \"%s\" из списка \"%s\"", user.Phone, item.Name, listName)
-	}
 
 	form := models.NotificationCreateForm{
 		TypeNotification: model.TypeNotification(),
-		Message:          message,
+		Template:         notifications.GoodsTemplate(model.TypeNotification()),
+		Params:           models.NotificationParams{ActorName: user.Name, Item: item.Name, List: model.ListName()},
 		UserId:           user.ID,
 		UserPhone:        user.Phone,
-		ListId:           model.Item().ListID,
-		ItemId:           models.NullString{String: model.Item().ID, Valid: true},
+		ListId:           item.ListID,
+		ItemId:           models.NullString{String: item.ID, Valid: true},
 	}
 
-	// Создать уведомления
-	for _, targetUserId := range model.TargetUserIds() {
-		form.TargetUserId = targetUserId
-		err := s.Repository.Create(&form)
+	inAppIds, pushIds := s.Recipients.Split(form.TypeNotification, form.ListId, model.TargetUserIds())
 
-		if err != nil {
-			log.Printf("Error create notification" + err.Error())
+	if err := deliver(&s.Repository, s.Renderer, s.PushChannel, s.Emails, form, inAppIds, pushIds); err != nil {
+		log.Printf("Error create notification" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Сохранить событие для каждого получателя. Уведомления создаст NotificationsFlusher,
+// объединив события одного автора в списке или собрав ежедневный дайджест
+func (s *GoodChangeListener) postpone(model *events.GoodsChangeEvent) error {
+	item := model.Item()
+	user := model.User()
+
+	inAppIds, pushIds, digestIds := s.Recipients.SplitDigest(model.TypeNotification(), item.ListID, model.TargetUserIds())
+
+	push := make(map[string]bool)
+	for _, id := range pushIds {
+		push[id] = true
+	}
+
+	pending := models.PendingNotification{
+		UserId:           user.ID,
+		UserName:         user.Name,
+		UserPhone:        user.Phone,
+		ListId:           item.ListID,
+		ListName:         model.ListName(),
+		ItemId:           item.ID,
+		ItemName:         item.Name,
+		TypeNotification: model.TypeNotification(),
+	}
+
+	for _, targetUserId := range inAppIds {
+		pending.TargetUserId = targetUserId
+		pending.Push = push[targetUserId]
+		pending.IsDigest = false
+
+		if err := s.Pending.Add(&pending); err != nil {
 			return err
 		}
-
 	}
 
-	if s.PushChannel == nil {
-		log.Println("[ERROR] PushChannel is nil")
-	} else {
-		pushMessage := services.PushNotificationMessage{Notification: form, TargetUserIds: model.TargetUserIds()}
-		s.PushChannel <- pushMessage
+	for _, targetUserId := range digestIds {
+		pending.TargetUserId = targetUserId
+		pending.Push = false
+		pending.IsDigest = true
+
+		if err := s.Pending.Add(&pending); err != nil {
+			return err
+		}
 	}
 
 	return nil
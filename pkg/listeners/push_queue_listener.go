@@ -0,0 +1,32 @@
+package listeners
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services"
+)
+
+// Постановка push в очередь отправки: одно задание на каждого получателя
+type PushQueueListener struct {
+	Repository repositories.PushJobsRepository
+}
+
+func (s *PushQueueListener) Run(channel chan services.PushNotificationMessage) {
+	for message := range channel {
+		s.Handle(message)
+	}
+}
+
+func (s *PushQueueListener) Handle(message services.PushNotificationMessage) {
+	payload := models.NewPushPayload(message.Notification)
+	payload.Silent = message.Silent
+
+	for _, targetUserId := range message.TargetUserIds {
+		job := models.PushJob{PushPayload: payload, TargetUserId: targetUserId}
+
+		if err := s.Repository.Add(&job); err != nil {
+			log.Errorln(err)
+		}
+	}
+}
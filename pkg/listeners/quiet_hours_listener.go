@@ -0,0 +1,93 @@
+package listeners
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/scheduler"
+	"shopingList/pkg/services"
+	"time"
+)
+
+// Фильтр push перед отправкой: получателям в тихие часы push откладывается, не отправляется или уходит без звука.
+// Читает общий канал push и ставит push остальным получателям в очередь отправки
+type QuietHoursListener struct {
+	Settings readModels.NotificationPreferencesReadRepository
+	Deferred repositories.DeferredPushesRepository
+	Queue    *PushQueueListener
+	Clock    scheduler.Clock
+}
+
+func (s *QuietHoursListener) Run(channel chan services.PushNotificationMessage) {
+	for message := range channel {
+		if err := s.Handle(message); err != nil {
+			log.Error("Error handle push message", err)
+		}
+	}
+}
+
+func (s *QuietHoursListener) Handle(message services.PushNotificationMessage) error {
+	deliver, silent := s.filter(message, s.Clock.Now())
+
+	if len(deliver) > 0 {
+		s.Queue.Handle(services.PushNotificationMessage{
+			Notification: message.Notification, TargetUserIds: deliver, Silent: message.Silent})
+	}
+
+	if len(silent) > 0 {
+		s.Queue.Handle(services.PushNotificationMessage{Notification: message.Notification, TargetUserIds: silent, Silent: true})
+	}
+
+	return nil
+}
+
+// Вернуть получателей, которым push отправляется сразу, и тех, кому он отправляется без звука
+func (s *QuietHoursListener) filter(message services.PushNotificationMessage, now time.Time) ([]string, []string) {
+	settings, err := s.Settings.GetSettingsForUsers(message.TargetUserIds)
+	if err != nil {
+		// Без настроек push отправляется всем
+		log.Errorln("Error get notification settings; " + err.Error())
+		return message.TargetUserIds, nil
+	}
+
+	deliver := make([]string, 0, len(message.TargetUserIds))
+	silent := make([]string, 0)
+
+	for _, targetUserId := range message.TargetUserIds {
+		userSettings, ok := settings[targetUserId]
+		if !ok {
+			deliver = append(deliver, targetUserId)
+			continue
+		}
+
+		until, quiet := userSettings.QuietUntil(now)
+		if !quiet {
+			deliver = append(deliver, targetUserId)
+			continue
+		}
+
+		if userSettings.QuietMode == models.QuietModeInApp {
+			continue
+		}
+
+		if userSettings.QuietMode == models.QuietModeSilent {
+			silent = append(silent, targetUserId)
+			continue
+		}
+
+		push := models.DeferredPush{
+			PushPayload:  models.NewPushPayload(message.Notification),
+			TargetUserId: targetUserId,
+			ReleaseAt:    until.Unix(),
+		}
+
+		if err := s.Deferred.Add(&push); err != nil {
+			// Лучше разбудить, чем потерять push
+			log.Errorln(err)
+			deliver = append(deliver, targetUserId)
+		}
+	}
+
+	return deliver, silent
+}
@@ -0,0 +1,63 @@
+package listeners
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+)
+
+// Отбор получателей уведомления по их настройкам: типу уведомления, каналу и отключенным спискам
+type RecipientsFilter struct {
+	Repository readModels.NotificationPreferencesReadRepository
+}
+
+// Разделить получателей на тех, для кого создается уведомление в приложении, и тех, кому отправляется push.
+// Если фильтр не задан или настройки не удалось получить, уведомляются все получатели
+func (s *RecipientsFilter) Split(typeNotification models.NotificationType, listId string, userIds []string) (inApp []string, push []string) {
+	inApp, push, digest := s.SplitDigest(typeNotification, listId, userIds)
+
+	// Без отложенной отправки дайджест получают сразу, как уведомление в приложении
+	return append(inApp, digest...), push
+}
+
+// То же, что Split, но получатели дайджеста возвращаются отдельно и не входят в inApp
+func (s *RecipientsFilter) SplitDigest(typeNotification models.NotificationType, listId string, userIds []string) (inApp []string, push []string, digest []string) {
+	if s == nil || len(userIds) == 0 {
+		return userIds, userIds, nil
+	}
+
+	channels, err := s.Repository.GetChannels(typeNotification, userIds)
+	if err != nil {
+		log.Errorln("Error get notification preferences; " + err.Error())
+		return userIds, userIds, nil
+	}
+
+	muted, err := s.Repository.GetMutedUserIds(listId, userIds)
+	if err != nil {
+		log.Errorln("Error get list mutes; " + err.Error())
+		return userIds, userIds, nil
+	}
+
+	for _, userId := range userIds {
+		channel, ok := channels[userId]
+		if !ok {
+			channel = models.DefaultNotificationChannel
+		}
+
+		switch channel {
+		case models.NotificationChannelNone:
+			continue
+		case models.NotificationChannelDigest:
+			digest = append(digest, userId)
+			continue
+		}
+
+		inApp = append(inApp, userId)
+
+		if channel == models.NotificationChannelPush && !muted[userId] {
+			push = append(push, userId)
+		}
+	}
+
+	return inApp, push, digest
+}
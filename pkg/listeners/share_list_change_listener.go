@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"shopingList/pkg/events"
 	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
 	"shopingList/pkg/repositories"
 	"shopingList/pkg/services"
 )
@@ -13,6 +14,15 @@ import (
 type ShareListChangeListener struct {
 	Repository  repositories.NotificationsRepository
 	PushChannel chan services.PushNotificationMessage
+
+	// Настройки уведомлений получателей. Если не задан, уведомляются все
+	Recipients *RecipientsFilter
+
+	// Тексты на языке получателей. Если не задан, используется язык по умолчанию
+	Renderer *notifications.Renderer
+
+	// Письма подписанным на email. Если не задан, письма не отправляются
+	Emails *notifications.EmailNotifier
 }
 
 func (s *ShareListChangeListener) Run(channel chan events.ShareListEvent) {
@@ -39,25 +49,28 @@ func (s *ShareListChangeListener) Handle(event interface{}) error {
 	user := model.User()
 	targetUserId := model.TargetUserId()
 
-	var message string
+	var template string
 	var typeNotification models.NotificationType
 
 	switch typeEvent {
 	case events.ShareListEventInvite:
-		message = fmt.Sprintf("Пользователь %d пригласил вас в список \"%s\"", user.Phone, list.Name)
+		template = notifications.TemplateListInvite
 		typeNotification = models.NotificationTypeListJoining
 	case events.ShareListEventAccept:
-		message = fmt.Sprintf("Пользователь %d присоединился к списку \"%s\"", user.Phone, list.Name)
+		template = notifications.TemplateListAccept
 		typeNotification = models.NotificationTypeListJoining
 	case events.ShareListEventRefuse:
-		message = fmt.Sprintf("Пользователь %d покинул список \"%s\"", user.Phone, list.Name)
+		template = notifications.TemplateListRefuse
 		typeNotification = models.NotificationTypeListDetachment
 	case events.ShareListEventDelete:
-		message = fmt.Sprintf("Пользователь %d отменил шаринг списка \"%s\"", user.Phone, list.Name)
+		template = notifications.TemplateListShareDelete
 		typeNotification = models.NotificationTypeListShareDelete
 	case events.ShareListEventListDelete:
-		message = fmt.Sprintf("Пользователь %d удалил список \"%s\"", user.Phone, list.Name)
+		template = notifications.TemplateListDelete
 		typeNotification = models.NotificationTypeListDelete
+	case events.ShareListEventRecurring:
+		template = notifications.TemplateListRecurring
+		typeNotification = models.NotificationTypeListRecurring
 	default:
 		err := errors.New(fmt.Sprintf("typeEvent is wrong, type: %s", typeEvent))
 		log.Fatalf("%+v", err)
@@ -65,25 +78,23 @@ func (s *ShareListChangeListener) Handle(event interface{}) error {
 
 	form := models.NotificationCreateForm{
 		TypeNotification: typeNotification,
-		Message:          message,
+		Template:         template,
+		Params:           models.NotificationParams{ActorName: user.Name, List: list.Name},
 		UserId:           user.ID,
 		UserPhone:        user.Phone,
 		ListId:           list.ID,
 		TargetUserId:     targetUserId,
 	}
 
-	err := s.Repository.Create(&form)
+	inAppIds, pushIds := s.Recipients.Split(typeNotification, list.ID, []string{targetUserId})
+	if len(inAppIds) == 0 {
+		return nil
+	}
 
+	err := deliver(&s.Repository, s.Renderer, s.PushChannel, s.Emails, form, inAppIds, pushIds)
 	if err != nil {
 		return errors.Wrap(err, "Error create notification in the ShareListChangeListener")
 	}
 
-	if s.PushChannel == nil {
-		log.Warn("[ERROR] PushChannel in ShareListChangeListener is nil")
-	} else {
-		pushMessage := services.PushNotificationMessage{Notification: form, TargetUserIds: []string{targetUserId}}
-		s.PushChannel <- pushMessage
-	}
-
 	return nil
 }
@@ -0,0 +1,22 @@
+package models
+
+const BarcodeSubmissionTableName = "sl_barcode_submissions"
+
+// Штрихкод, которого нет в справочнике, привязанный пользователем к своему товару
+type BarcodeSubmission struct {
+	Barcode       string `json:"barcode"`
+	UserID        string `json:"user_id"`
+	UserProductID string `json:"user_product_id"`
+	Name          string `json:"name"`
+	CategoryID    int64  `json:"category_id"`
+	CreatedAt     int64  `json:"created_at"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+// Сводка по штрихкоду, который пользователи предлагают добавить в справочник
+type BarcodeSubmissionStat struct {
+	Barcode    string
+	Name       string
+	CategoryID int64
+	UsersCount int
+}
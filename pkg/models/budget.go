@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+)
+
+const BudgetTableName = "sl_budgets"
+
+// Бюджет пользователя.
+// Если указан список, то это бюджет списка, иначе - месячный бюджет пользователя по отмеченным им товарам
+type Budget struct {
+	OwnerID    string  `json:"owner_id" valid:"uuid,required"`
+	ListID     string  `json:"list_id" valid:"uuid"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency" valid:"matches(^[A-Z]{3}$),required"`
+	NotifiedAt int64   `json:"notified_at"` // Время последнего уведомления о превышении
+	CreatedAt  int64   `json:"created_at"`
+	UpdatedAt  int64   `json:"updated_at"`
+	IsDeleted  bool    `json:"is_deleted"`
+}
+
+func (s *Budget) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	if s.Amount <= 0 {
+		return false, errors.New("amount must be positive")
+	}
+
+	return true, nil
+}
+
+func (s *Budget) IsMonthly() bool {
+	return s.ListID == ""
+}
@@ -2,20 +2,27 @@ package models
 
 import (
 	"errors"
+	"github.com/asaskevich/govalidator"
+	"strings"
+	"time"
 )
 
 // AppConfig - base app config structure
 type AppConfig struct {
-	Server                  ServerConfig       `json:"server"`
-	Database                DatabaseConfig     `json:"database"`
-	SmsEnabled              bool               `json:"smsEnabled"`
-	SmsAeroConfig           SmsAeroConfig      `json:"smsAeroConfig"`
-	FirebaseCredentialsFile string             `json:"firebaseCredentialsFile"`
-	RedisConfig             RedisConfig        `json:"redisConfig"`
-	LoginLimiterConfig      LoginLimiterConfig `json:"loginLimiter"`
-	LogLevel                string             `json:"logLevel"`
-	TelegramBotToken        string             `json:"tgBotToken"`
-	DebugPhones             []int64            `json:"debugPhones"`
+	Server                  ServerConfig        `json:"server"`
+	Database                DatabaseConfig      `json:"database"`
+	SmsEnabled              bool                `json:"smsEnabled"`
+	SmsAeroConfig           SmsAeroConfig       `json:"smsAeroConfig"`
+	FirebaseCredentialsFile string              `json:"firebaseCredentialsFile"`
+	RedisConfig             RedisConfig         `json:"redisConfig"`
+	LoginLimiterConfig      LoginLimiterConfig  `json:"loginLimiter"`
+	LogLevel                string              `json:"logLevel"`
+	TelegramBotToken        string              `json:"tgBotToken"`
+	DebugPhones             []int64             `json:"debugPhones"`
+	PushQueue               PushQueueConfig     `json:"pushQueue"`
+	Push                    PushConfig          `json:"push"`
+	Smtp                    SmtpConfig          `json:"smtp"`
+	Notifications           NotificationsConfig `json:"notifications"`
 }
 
 // ServerConfig - server config
@@ -63,6 +70,51 @@ func (s *AppConfig) HasFirebaseCredentials() bool {
 	return s.FirebaseCredentialsFile != ""
 }
 
+// Есть ли чем отправлять push
+func (s *AppConfig) HasPushProviders() bool {
+	return len(s.PushProviders()) > 0
+}
+
+// Заданы ли настройки push, которые без провайдеров не используются.
+// Например, ключ APNs без push.providers или файл sink без push.sink
+func (s *AppConfig) HasPushSettings() bool {
+	return s.Push.APNs.KeyFile != "" || s.Push.SinkFile != ""
+}
+
+// Сервис отправки push для каждой платформы токенов.
+// Без явной настройки все платформы отправляются через FCM, если заданы его credentials.
+// Sink используется только по явной настройке. Без провайдеров возвращается пустой список и push не отправляются
+func (s *AppConfig) PushProviders() map[string]string {
+	providers := make(map[string]string)
+
+	if len(s.Push.Providers) > 0 {
+		for platform, provider := range s.Push.Providers {
+			providers[platform] = provider
+		}
+	} else if s.HasFirebaseCredentials() {
+		providers[FCMTokenPlatformIOS] = PushProviderFCM
+		providers[FCMTokenPlatformAndriod] = PushProviderFCM
+	}
+
+	// Подписки браузеров отправляются через Web Push, если заданы ключи VAPID
+	if _, ok := providers[FCMTokenPlatformWeb]; !ok && s.Push.WebPush.PrivateKey != "" {
+		providers[FCMTokenPlatformWeb] = PushProviderWebPush
+	}
+
+	if len(providers) == 0 && s.Push.Sink {
+		providers[FCMTokenPlatformIOS] = PushProviderSink
+		providers[FCMTokenPlatformAndriod] = PushProviderSink
+		providers[FCMTokenPlatformWeb] = PushProviderSink
+	}
+
+	return providers
+}
+
+// Есть ли чем отправлять email
+func (s *AppConfig) HasSmtp() bool {
+	return s.Smtp.Host != ""
+}
+
 type RedisConfig struct {
 	Address  string `json:"address"`
 	Password string `json:"password"`
@@ -73,3 +125,145 @@ type LoginLimiterConfig struct {
 	SeqLimitSeconds int `json:"seqLimitSeconds"`
 	DailyLimitCount int `json:"dailyLimitCount"`
 }
+
+// Настройки очереди отправки push
+type PushQueueConfig struct {
+	Concurrency        int `json:"concurrency"`
+	MaxAttempts        int `json:"maxAttempts"`
+	BaseBackoffSeconds int `json:"baseBackoffSeconds"`
+	MaxBackoffSeconds  int `json:"maxBackoffSeconds"`
+}
+
+// Заполнить незаданные значения значениями по умолчанию
+func (s PushQueueConfig) WithDefaults() PushQueueConfig {
+	if s.Concurrency <= 0 {
+		s.Concurrency = 4
+	}
+
+	if s.MaxAttempts <= 0 {
+		s.MaxAttempts = 8
+	}
+
+	if s.BaseBackoffSeconds <= 0 {
+		s.BaseBackoffSeconds = 10
+	}
+
+	if s.MaxBackoffSeconds <= 0 {
+		s.MaxBackoffSeconds = 3600
+	}
+
+	return s
+}
+
+const PushProviderFCM = "fcm"
+const PushProviderAPNs = "apns"
+const PushProviderSink = "sink"
+const PushProviderWebPush = "webpush"
+
+// Настройки сервисов отправки push
+type PushConfig struct {
+	Providers map[string]string `json:"providers"` // Платформа токена -> fcm, apns, webpush или sink
+	APNs      APNsConfig        `json:"apns"`
+	WebPush   WebPushConfig     `json:"webPush"`
+	Sink      bool              `json:"sink"`     // Для разработки: без других провайдеров все push пишутся в sink
+	SinkFile  string            `json:"sinkFile"` // Файл для sink, без него payload пишется в лог
+}
+
+func (s *PushConfig) Validate() error {
+	for platform, provider := range s.Providers {
+		if !govalidator.IsIn(provider, PushProviderFCM, PushProviderAPNs, PushProviderSink, PushProviderWebPush) {
+			return errors.New("unknown push provider " + provider + " for platform " + platform)
+		}
+	}
+
+	return nil
+}
+
+// Настройки APNs с авторизацией по ключу .p8
+type APNsConfig struct {
+	KeyFile    string `json:"keyFile"`
+	KeyID      string `json:"keyId"`
+	TeamID     string `json:"teamId"`
+	Topic      string `json:"topic"` // Bundle id приложения
+	Production bool   `json:"production"`
+}
+
+func (s *APNsConfig) Validate() (bool, error) {
+	if s.KeyFile == "" {
+		return false, errors.New("keyFile is empty")
+	}
+
+	if s.KeyID == "" {
+		return false, errors.New("keyId is empty")
+	}
+
+	if s.TeamID == "" {
+		return false, errors.New("teamId is empty")
+	}
+
+	if s.Topic == "" {
+		return false, errors.New("topic is empty")
+	}
+
+	return true, nil
+}
+
+// Настройки SMTP. Для разработки подходит локальный перехватчик писем без авторизации, например MailHog на порту 1025
+type SmtpConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+	FromName string `json:"fromName"`
+}
+
+func (s *SmtpConfig) Validate() (bool, error) {
+	if s.Host == "" {
+		return false, errors.New("host is empty")
+	}
+
+	if s.Port <= 0 {
+		return false, errors.New("port is empty")
+	}
+
+	if !govalidator.IsEmail(s.From) {
+		return false, errors.New("from is not email")
+	}
+
+	return true, nil
+}
+
+// Ключи VAPID для Web Push. Закрытый ключ P-256 в base64url, открытый вычисляется из него
+type WebPushConfig struct {
+	PrivateKey string `json:"privateKey"`
+	Subject    string `json:"subject"` // Контакт для push-сервисов: mailto: или https:
+}
+
+func (s *WebPushConfig) Validate() (bool, error) {
+	if s.PrivateKey == "" {
+		return false, errors.New("privateKey is empty")
+	}
+
+	if !strings.HasPrefix(s.Subject, "mailto:") && !strings.HasPrefix(s.Subject, "https:") {
+		return false, errors.New("subject must be mailto: or https: url")
+	}
+
+	return true, nil
+}
+
+// Настройки хранения уведомлений
+type NotificationsConfig struct {
+	RetentionDays int `json:"retentionDays"` // Сколько дней хранить уведомления, по умолчанию 180
+}
+
+const DefaultNotificationsRetentionDays = 180
+
+func (s NotificationsConfig) Retention() time.Duration {
+	days := s.RetentionDays
+	if days <= 0 {
+		days = DefaultNotificationsRetentionDays
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,23 @@
+package models
+
+import "errors"
+
+const ContactAliasTableName = "sl_contact_aliases"
+
+// Как пользователь называет другого участника списков. Показывается в уведомлениях вместо имени
+type ContactAlias struct {
+	UserId string `json:"user_id"`
+	Alias  string `json:"alias"`
+}
+
+func (s *ContactAlias) Validate() (bool, error) {
+	if s.Alias == "" {
+		return false, errors.New("alias is empty")
+	}
+
+	if len([]rune(s.Alias)) > 100 {
+		return false, errors.New("alias is too long")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,11 @@
+package models
+
+const DeferredPushTableName = "sl_deferred_pushes"
+
+// Push, отложенный до конца тихих часов получателя
+type DeferredPush struct {
+	PushPayload
+	ID           int64
+	TargetUserId string
+	ReleaseAt    int64
+}
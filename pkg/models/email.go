@@ -0,0 +1,17 @@
+package models
+
+// Получатель email: адрес и язык писем
+type EmailRecipient struct {
+	UserId string
+	Email  string
+	Name   string
+	Locale string
+}
+
+// Активность в списке за период для еженедельной сводки
+type ListActivity struct {
+	ListId    string
+	Name      string
+	Added     int // Добавлено товаров
+	Purchased int // Куплено товаров
+}
@@ -1,21 +1,54 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/asaskevich/govalidator"
+	"shopingList/pkg/units"
 )
 
+const DefaultCurrency = "RUB"
+
 type ListItem struct {
-	ID         string     `json:"id" valid:"uuid,required"`
-	Name       string     `json:"name" valid:"stringlength(1|140),required"`
-	Value      string     `json:"value" valid:"stringlength(0|50),required"`
-	IsMarked   bool       `json:"is_marked" valid:"required"`
-	UserMarked NullString `json:"user_marked"`
-	ListID     string     `json:"list_id" valid:"uuid,required"`
-	CreatedAt  int64      `json:"created_at" valid:"int,required"`
-	UpdatedAt  int64      `json:"updated_at" valid:"int,required"`
-	ReceivedAt int64      `json:"received_at"`
-	IsDeleted  bool       `json:"is_deleted" valid:"required"`
+	ID              string      `json:"id" valid:"uuid,required"`
+	Name            string      `json:"name" valid:"stringlength(1|140),required"`
+	Value           string      `json:"value" valid:"stringlength(0|50)"`
+	Quantity        NullFloat64 `json:"quantity"`
+	Unit            string      `json:"unit" valid:"in(pcs|g|kg|ml|l|pack)"`
+	Price           NullFloat64 `json:"price"`
+	Currency        string      `json:"currency" valid:"matches(^[A-Z]{3}$)"`
+	ProductID       NullInt64   `json:"product_id"`       // Товар из справочника
+	CategoryID      NullInt64   `json:"category_id"`      // Категория из справочника
+	MatchConfidence NullFloat64 `json:"match_confidence"` // Уверенность автосопоставления с товаром справочника, 0..1
+	IsMarked        bool        `json:"is_marked" valid:"required"`
+	UserMarked      NullString  `json:"user_marked"`
+	ListID          string      `json:"list_id" valid:"uuid,required"`
+	CreatedAt       int64       `json:"created_at" valid:"int,required"`
+	UpdatedAt       int64       `json:"updated_at" valid:"int,required"`
+	ReceivedAt      int64       `json:"received_at"`
+	IsDeleted       bool        `json:"is_deleted" valid:"required"`
+	PriceSet        bool        `json:"-"` // Клиент передал поле price (в том числе null)
+}
+
+// UnmarshalJSON для ListItem запоминает, передано ли поле price,
+// чтобы отличить старого клиента без цены от явного сброса цены
+func (s *ListItem) UnmarshalJSON(b []byte) error {
+	type listItem ListItem
+
+	var item listItem
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	_, item.PriceSet = fields["price"]
+	*s = ListItem(item)
+
+	return nil
 }
 
 func (s *ListItem) Validate() (bool, error) {
@@ -32,6 +65,23 @@ func (s *ListItem) Validate() (bool, error) {
 		return false, errors.New("UserMarked format is wrong")
 	}
 
+	// Value может быть пустым, только если передано количество. Тогда value заполняется на сервере
+	if s.Value == "" && !s.Quantity.Valid {
+		return false, errors.New("Value is empty")
+	}
+
+	if s.Quantity.Valid && s.Quantity.Float64 <= 0 {
+		return false, errors.New("Quantity must be greater than 0")
+	}
+
+	if s.Unit != "" && !s.Quantity.Valid {
+		return false, errors.New("Unit is set without Quantity")
+	}
+
+	if s.Price.Valid && s.Price.Float64 < 0 {
+		return false, errors.New("Price must not be negative")
+	}
+
 	if s.CreatedAt == 0 {
 		return false, errors.New("CreatedAt is 0")
 	}
@@ -53,8 +103,60 @@ func (s *ListItem) IsEqual(item *ListItem) bool {
 		s.ListID == item.ListID &&
 		s.Name == item.Name &&
 		s.Value == item.Value &&
+		s.Quantity == item.Quantity &&
+		s.Unit == item.Unit &&
+		s.Price == item.Price &&
+		s.Currency == item.Currency &&
+		s.ProductID == item.ProductID &&
+		s.CategoryID == item.CategoryID &&
 		s.IsMarked == item.IsMarked &&
 		s.UserMarked.String == item.UserMarked.String &&
 		s.UpdatedAt == item.UpdatedAt &&
 		s.IsDeleted == item.IsDeleted
 }
+
+// Заполнить количество из поля value для старых клиентов, которые не передают quantity.
+// Если количество передано, value всегда пересобирается из него, чтобы старые клиенты видели актуальное значение
+func (s *ListItem) NormalizeQuantity() {
+	if !s.Quantity.Valid {
+		s.Unit = ""
+
+		if q, ok := units.Parse(s.Value); ok {
+			s.Quantity = NewNullFloat64(q.Amount)
+			s.Unit = string(q.Unit)
+		}
+
+		return
+	}
+
+	if s.Unit == "" {
+		s.Unit = string(units.Pieces)
+	}
+
+	s.Value = units.Format(s.GetQuantity())
+}
+
+// Проставить валюту по умолчанию для цены без валюты
+func (s *ListItem) NormalizePrice() {
+	if !s.Price.Valid {
+		s.Currency = ""
+		return
+	}
+
+	if s.Currency == "" {
+		s.Currency = DefaultCurrency
+	}
+}
+
+// Вернуть структурированное количество товара
+func (s *ListItem) GetQuantity() units.Quantity {
+	return units.Quantity{Amount: s.Quantity.Float64, Unit: units.Unit(s.Unit)}
+}
+
+// Как часто название товара встречается в списках пользователя
+type ItemNameStat struct {
+	Name       string
+	ProductID  NullInt64
+	CategoryID NullInt64
+	Count      int
+}
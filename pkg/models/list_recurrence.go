@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"time"
+)
+
+const ListRecurrenceTableName = "sl_list_recurrences"
+
+const (
+	RecurrenceTypeDays    = "days"    // Каждые N дней
+	RecurrenceTypeWeekly  = "weekly"  // Раз в неделю в указанный день недели
+	RecurrenceTypeMonthly = "monthly" // Раз в месяц в указанный день месяца
+)
+
+// Правило повторения для списка-шаблона
+type ListRecurrence struct {
+	ListID       string `json:"list_id" valid:"uuid,required"`
+	OwnerID      string `json:"owner_id" valid:"uuid,required"`
+	Type         string `json:"type" valid:"in(days|weekly|monthly),required"`
+	IntervalDays int    `json:"interval_days"`
+	WeekDay      int    `json:"week_day"`  // 0 - воскресенье, как в time.Weekday
+	MonthDay     int    `json:"month_day"` // Если в месяце меньше дней, то используется последний день месяца
+	Hour         int    `json:"hour"`      // Час запуска в часовом поясе владельца из настроек уведомлений
+	NextRunAt    int64  `json:"next_run_at"`
+	LastRunAt    int64  `json:"last_run_at"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+	IsDeleted    bool   `json:"is_deleted"`
+}
+
+func (s *ListRecurrence) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	if s.Hour < 0 || s.Hour > 23 {
+		return false, errors.New("hour must be in range 0-23")
+	}
+
+	switch s.Type {
+	case RecurrenceTypeDays:
+		if s.IntervalDays < 1 || s.IntervalDays > 365 {
+			return false, errors.New("interval_days must be in range 1-365")
+		}
+	case RecurrenceTypeWeekly:
+		if s.WeekDay < 0 || s.WeekDay > 6 {
+			return false, errors.New("week_day must be in range 0-6")
+		}
+	case RecurrenceTypeMonthly:
+		if s.MonthDay < 1 || s.MonthDay > 31 {
+			return false, errors.New("month_day must be in range 1-31")
+		}
+	}
+
+	return true, nil
+}
+
+// Вернуть время следующего запуска строго после after, в UTC. День и час считаются в location,
+// поэтому при переходе на летнее время запуск остается в тот же час по местному времени.
+// Для типа "каждые N дней" отсчет ведется от предыдущего запланированного запуска,
+// пропущенные запуски (например, при остановленном сервере) не накапливаются
+func (s *ListRecurrence) NextRunAfter(after time.Time, location *time.Location) time.Time {
+	after = after.In(location)
+	candidate := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, 0, 0, 0, location)
+
+	switch s.Type {
+	case RecurrenceTypeDays:
+		if s.NextRunAt != 0 {
+			previous := time.Unix(s.NextRunAt, 0).In(location)
+			candidate = time.Date(previous.Year(), previous.Month(), previous.Day(), s.Hour, 0, 0, 0, location)
+		}
+
+		for !candidate.After(after) {
+			candidate = candidate.AddDate(0, 0, s.IntervalDays)
+		}
+	case RecurrenceTypeWeekly:
+		for candidate.Weekday() != time.Weekday(s.WeekDay) || !candidate.After(after) {
+			candidate = candidate.AddDate(0, 0, 1)
+		}
+	case RecurrenceTypeMonthly:
+		candidate = monthDayInMonth(after.Year(), after.Month(), s.MonthDay, s.Hour, location)
+		if !candidate.After(after) {
+			candidate = monthDayInMonth(after.Year(), after.Month()+1, s.MonthDay, s.Hour, location)
+		}
+	}
+
+	return candidate.UTC()
+}
+
+func monthDayInMonth(year int, month time.Month, day int, hour int, location *time.Location) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(year, month, day, hour, 0, 0, 0, location)
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+
+	return location
+}
+
+func TestListRecurrenceNextRunAfter(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	moscow := mustLoadLocation(t, "Europe/Moscow")
+
+	cases := []struct {
+		name       string
+		recurrence ListRecurrence
+		after      time.Time
+		location   *time.Location
+		want       time.Time
+	}{
+		{
+			name:       "monthly later today",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 15, Hour: 9},
+			after:      time.Date(2023, 3, 15, 8, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 3, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly exactly at run time moves to next month",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 15, Hour: 9},
+			after:      time.Date(2023, 3, 15, 9, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 4, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly day 31 in 30-day month",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 31, Hour: 9},
+			after:      time.Date(2023, 4, 10, 0, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 4, 30, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly day 31 after january run",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 31, Hour: 9},
+			after:      time.Date(2023, 1, 31, 10, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly day 29 in leap february",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 29, Hour: 9},
+			after:      time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly day 29 in common february",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 29, Hour: 9},
+			after:      time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "monthly across year end",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 5, Hour: 9},
+			after:      time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekly next saturday",
+			recurrence: ListRecurrence{Type: RecurrenceTypeWeekly, WeekDay: int(time.Saturday), Hour: 9},
+			after:      time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 3, 4, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekly same day after run time",
+			recurrence: ListRecurrence{Type: RecurrenceTypeWeekly, WeekDay: int(time.Saturday), Hour: 9},
+			after:      time.Date(2023, 3, 4, 10, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "weekly sunday",
+			recurrence: ListRecurrence{Type: RecurrenceTypeWeekly, WeekDay: int(time.Sunday), Hour: 0},
+			after:      time.Date(2023, 3, 4, 23, 59, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "every n days from previous run",
+			recurrence: ListRecurrence{Type: RecurrenceTypeDays, IntervalDays: 3, Hour: 9,
+				NextRunAt: time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC).Unix()},
+			after:    time.Date(2023, 3, 5, 10, 0, 0, 0, time.UTC),
+			location: time.UTC,
+			want:     time.Date(2023, 3, 7, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "every n days first run",
+			recurrence: ListRecurrence{Type: RecurrenceTypeDays, IntervalDays: 2, Hour: 9},
+			after:      time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+			location:   time.UTC,
+			want:       time.Date(2023, 3, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "hour in owner time zone",
+			recurrence: ListRecurrence{Type: RecurrenceTypeWeekly, WeekDay: int(time.Saturday), Hour: 9},
+			after:      time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC),
+			location:   moscow,
+			want:       time.Date(2023, 3, 4, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "day in owner time zone differs from utc",
+			recurrence: ListRecurrence{Type: RecurrenceTypeWeekly, WeekDay: int(time.Saturday), Hour: 1},
+			after:      time.Date(2023, 3, 3, 21, 30, 0, 0, time.UTC), // В Москве уже суббота 00:30
+			location:   moscow,
+			want:       time.Date(2023, 3, 3, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "same local hour after dst start",
+			recurrence: ListRecurrence{Type: RecurrenceTypeDays, IntervalDays: 1, Hour: 9,
+				NextRunAt: time.Date(2023, 3, 25, 8, 0, 0, 0, time.UTC).Unix()},
+			after:    time.Date(2023, 3, 25, 8, 0, 1, 0, time.UTC),
+			location: berlin,
+			want:     time.Date(2023, 3, 26, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "same local hour after dst end",
+			recurrence: ListRecurrence{Type: RecurrenceTypeMonthly, MonthDay: 1, Hour: 9},
+			after:      time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC),
+			location:   berlin,
+			want:       time.Date(2023, 11, 1, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.recurrence.NextRunAfter(c.after, c.location)
+			if !got.Equal(c.want) {
+				t.Errorf("NextRunAfter(%s) = %s, want %s", c.after, got, c.want)
+			}
+
+			if got.Location() != time.UTC {
+				t.Errorf("result must be in UTC, got %s", got.Location())
+			}
+		})
+	}
+}
@@ -18,30 +18,47 @@ const (
 	NotificationTypeGoodsDelete     = 8  // Удаление товара
 	NotificationTypeListShareDelete = 9  // Удаление шаринга
 	NotificationTypeListDelete      = 10 // Удаление списка
+	NotificationTypeListRecurring   = 11 // Создание списка по расписанию
+	NotificationTypeBudgetExceeded  = 12 // Превышение бюджета
+	NotificationTypeGoodsDigest     = 13 // Дайджест изменений товаров за день
 )
 
 type NotificationType int
 
 type Notification struct {
-	TypeNotification NotificationType `json:"type"  valid:"uuid,required"`
-	ID               string           `json:"id" valid:"uuid,required"`
-	Message          string           `json:"message" valid:"stringlength(1|255),required"`
-	UserId           string           `json:"user_id" valid:"uuid"`
-	UserPhone        int64            `json:"user_phone" valid:"stringlength(0|10)"`
-	ListId           string           `json:"list_id" valid:"uuid"`
-	ItemId           NullString       `json:"item_id" valid:"uuid"`
-	TargetUserId     string           `json:"-"`
-	CreatedAt        int64            `json:"created_at" valid:"int,required"`
+	TypeNotification NotificationType   `json:"type"  valid:"uuid,required"`
+	ID               string             `json:"id" valid:"uuid,required"`
+	Message          string             `json:"message" valid:"stringlength(1|255),required"`
+	Template         string             `json:"template"`
+	Params           NotificationParams `json:"params"`
+	UserId           string             `json:"user_id" valid:"uuid"`
+	UserPhone        int64              `json:"user_phone" valid:"stringlength(0|10)"`
+	ListId           string             `json:"list_id" valid:"uuid"`
+	ItemId           NullString         `json:"item_id" valid:"uuid"`
+	TargetUserId     string             `json:"-"`
+	IsRead           bool               `json:"is_read"`
+	ReadAt           NullInt64          `json:"read_at"`
+	CreatedAt        int64              `json:"created_at" valid:"int,required"`
+}
+
+// Состояние прочтения уведомления для синхронизации между устройствами пользователя
+type NotificationState struct {
+	ID        string    `json:"id"`
+	ReadAt    NullInt64 `json:"read_at"`
+	IsDeleted bool      `json:"is_deleted"`
+	UpdatedAt int64     `json:"updated_at"`
 }
 
 type NotificationCreateForm struct {
-	TypeNotification NotificationType `json:"type"  valid:"uuid,required"`
-	Message          string           `json:"name" valid:"stringlength(1|255),required"`
-	UserId           string           `json:"user_id" valid:"uuid"`
-	UserPhone        int64            `json:"user_phone" valid:"stringlength(0|10)"`
-	ListId           string           `json:"list_id" valid:"uuid"`
-	ItemId           NullString       `json:"item_id" valid:"uuid"`
-	TargetUserId     string           `json:"-" valid:"uuid,required"`
+	TypeNotification NotificationType   `json:"type"  valid:"uuid,required"`
+	Message          string             `json:"name" valid:"stringlength(1|255),required"`
+	Template         string             `json:"template" valid:"-"`
+	Params           NotificationParams `json:"params" valid:"-"`
+	UserId           string             `json:"user_id" valid:"uuid"`
+	UserPhone        int64              `json:"user_phone" valid:"stringlength(0|10)"`
+	ListId           string             `json:"list_id" valid:"uuid"`
+	ItemId           NullString         `json:"item_id" valid:"uuid"`
+	TargetUserId     string             `json:"-" valid:"uuid,required"`
 }
 
 func (s *NotificationCreateForm) Validate() (bool, error) {
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Позиция в ленте уведомлений: дата и id последнего загруженного уведомления.
+// Клиент получает ее строкой и передает обратно без изменений
+type NotificationCursor struct {
+	CreatedAt int64
+	ID        string
+}
+
+func (s NotificationCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(s.CreatedAt, 10) + ":" + s.ID))
+}
+
+func DecodeNotificationCursor(value string) (NotificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return NotificationCursor{}, errors.New("cursor is wrong")
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return NotificationCursor{}, errors.New("cursor is wrong")
+	}
+
+	createdAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return NotificationCursor{}, errors.New("cursor is wrong")
+	}
+
+	return NotificationCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// Отбор уведомлений в ленте. Пустые поля не ограничивают выборку
+type NotificationFilter struct {
+	Types  []NotificationType
+	ListId string
+}
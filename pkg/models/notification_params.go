@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Параметры шаблона уведомления. Хранятся вместе с уведомлением, чтобы текст можно было построить заново,
+// например на другом языке
+type NotificationParams struct {
+	ActorName string                  `json:"actor_name,omitempty"` // Имя автора на момент события
+	Item      string                  `json:"item,omitempty"`
+	List      string                  `json:"list,omitempty"`
+	Count     int                     `json:"count,omitempty"`
+	Spent     float64                 `json:"spent,omitempty"`
+	Budget    float64                 `json:"budget,omitempty"`
+	Currency  string                  `json:"currency,omitempty"`
+	Lists     []NotificationListCount `json:"lists,omitempty"` // Списки в дайджесте
+}
+
+// Количество изменений в списке для дайджеста
+type NotificationListCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (s NotificationParams) Value() (driver.Value, error) {
+	value, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(value), nil
+}
+
+func (s *NotificationParams) Scan(value interface{}) error {
+	*s = NotificationParams{}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(v) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(v, s)
+	case string:
+		if v == "" {
+			return nil
+		}
+
+		return json.Unmarshal([]byte(v), s)
+	}
+
+	return errors.New("unsupported type for NotificationParams")
+}
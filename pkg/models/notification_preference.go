@@ -0,0 +1,96 @@
+package models
+
+import "errors"
+
+const NotificationPreferenceTableName = "sl_notification_preferences"
+const NotificationListMuteTableName = "sl_notification_list_mutes"
+
+// Каналы доставки уведомлений
+const (
+	NotificationChannelPush   = "push"   // В приложении и push
+	NotificationChannelInApp  = "in_app" // Только в приложении
+	NotificationChannelNone   = "none"   // Не уведомлять
+	NotificationChannelDigest = "digest" // Раз в день в дайджесте, только для товаров
+)
+
+// Канал по умолчанию, если пользователь не менял настройку
+const DefaultNotificationChannel = NotificationChannelPush
+
+// Типы уведомлений по товарам, их можно получать в дайджесте
+var GoodsNotificationTypes = []NotificationType{
+	NotificationTypeGoodsCreate,
+	NotificationTypeGoodsCheck,
+	NotificationTypeGoodsUncheck,
+	NotificationTypeGoodsChange,
+	NotificationTypeGoodsDelete,
+}
+
+// Типы уведомлений, которые пользователь может настроить
+var NotificationTypes = []NotificationType{
+	NotificationTypeListInvite,
+	NotificationTypeListJoining,
+	NotificationTypeListDetachment,
+	NotificationTypeGoodsCreate,
+	NotificationTypeGoodsCheck,
+	NotificationTypeGoodsUncheck,
+	NotificationTypeGoodsChange,
+	NotificationTypeGoodsDelete,
+	NotificationTypeListShareDelete,
+	NotificationTypeListDelete,
+	NotificationTypeListRecurring,
+	NotificationTypeBudgetExceeded,
+}
+
+func IsNotificationType(value NotificationType) bool {
+	for _, t := range NotificationTypes {
+		if t == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func IsGoodsNotificationType(value NotificationType) bool {
+	for _, t := range GoodsNotificationTypes {
+		if t == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+type NotificationPreference struct {
+	Type    NotificationType `json:"type"`
+	Channel string           `json:"channel"`
+	Email   bool             `json:"email"` // Дублировать уведомление на email
+}
+
+func (s *NotificationPreference) Validate() (bool, error) {
+	if !IsNotificationType(s.Type) {
+		return false, errors.New("unknown notification type")
+	}
+
+	switch s.Channel {
+	case NotificationChannelPush, NotificationChannelInApp, NotificationChannelNone:
+	case NotificationChannelDigest:
+		if !IsGoodsNotificationType(s.Type) {
+			return false, errors.New("digest is available only for goods notifications")
+		}
+	default:
+		return false, errors.New("unknown notification channel")
+	}
+
+	return true, nil
+}
+
+// Отключение push по списку. Уведомления в приложении продолжают создаваться
+type NotificationListMute struct {
+	ListID     string    `json:"list_id"`
+	MutedUntil NullInt64 `json:"muted_until"` // Пустое значение - без ограничения по времени
+}
+
+func (s *NotificationListMute) IsActive(now int64) bool {
+	return !s.MutedUntil.Valid || s.MutedUntil.Int64 > now
+}
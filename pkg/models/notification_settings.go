@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"shopingList/pkg/i18n"
+	"strconv"
+	"time"
+)
+
+const NotificationSettingsTableName = "sl_notification_settings"
+
+// Что делать с push в тихие часы
+const (
+	QuietModeDefer  = "defer"  // Отложить до конца тихих часов
+	QuietModeInApp  = "in_app" // Не отправлять, уведомление останется в приложении
+	QuietModeSilent = "silent" // Отправить сразу без звука и баннера
+)
+
+const DefaultTimezone = "UTC"
+
+const minutesInDay = 24 * 60
+
+// Часовой пояс, язык и тихие часы пользователя. Время начала и конца - минуты от полуночи по местному времени
+type NotificationSettings struct {
+	Timezone    string    `json:"timezone"`
+	Locale      string    `json:"locale"` // Язык уведомлений
+	QuietStart  NullInt64 `json:"quiet_start"`
+	QuietEnd    NullInt64 `json:"quiet_end"`
+	QuietMode   string    `json:"quiet_mode"`
+	WeeklyEmail bool      `json:"weekly_email"` // Еженедельная сводка по спискам на email
+}
+
+func NewNotificationSettings() NotificationSettings {
+	return NotificationSettings{Timezone: DefaultTimezone, Locale: i18n.DefaultLocale, QuietMode: QuietModeDefer}
+}
+
+func (s *NotificationSettings) Validate() (bool, error) {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return false, errors.New("unknown timezone")
+	}
+
+	if !i18n.IsSupported(s.Locale) {
+		return false, errors.New("unsupported locale")
+	}
+
+	if s.QuietStart.Valid != s.QuietEnd.Valid {
+		return false, errors.New("quiet_start and quiet_end must be set together")
+	}
+
+	for _, value := range []NullInt64{s.QuietStart, s.QuietEnd} {
+		if value.Valid && (value.Int64 < 0 || value.Int64 >= minutesInDay) {
+			return false, errors.New("quiet hours must be in minutes from 0 to " + strconv.Itoa(minutesInDay-1))
+		}
+	}
+
+	switch s.QuietMode {
+	case QuietModeDefer, QuietModeInApp, QuietModeSilent:
+	default:
+		return false, errors.New("unknown quiet_mode")
+	}
+
+	return true, nil
+}
+
+func (s *NotificationSettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// Если сейчас тихие часы, вернуть время их окончания. Интервал может переходить через полночь
+func (s *NotificationSettings) QuietUntil(now time.Time) (time.Time, bool) {
+	if !s.QuietStart.Valid || !s.QuietEnd.Valid || s.QuietStart.Int64 == s.QuietEnd.Int64 {
+		return time.Time{}, false
+	}
+
+	local := now.In(s.Location())
+	minute := int64(local.Hour()*60 + local.Minute())
+	start, end := s.QuietStart.Int64, s.QuietEnd.Int64
+
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else {
+		quiet = minute >= start || minute < end
+	}
+
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), int(end/60), int(end%60), 0, 0, local.Location())
+	if !until.After(local) {
+		until = time.Date(local.Year(), local.Month(), local.Day()+1, int(end/60), int(end%60), 0, 0, local.Location())
+	}
+
+	return until.UTC(), true
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationSettingsQuietUntil(t *testing.T) {
+	night := NotificationSettings{Timezone: "Europe/Berlin", QuietStart: NewNullInt64(22 * 60), QuietEnd: NewNullInt64(7 * 60)}
+	day := NotificationSettings{Timezone: "UTC", QuietStart: NewNullInt64(13 * 60), QuietEnd: NewNullInt64(15*60 + 30)}
+
+	cases := []struct {
+		name      string
+		settings  NotificationSettings
+		now       time.Time
+		wantQuiet bool
+		want      time.Time
+	}{
+		{
+			name:      "window inside day",
+			settings:  day,
+			now:       time.Date(2023, 6, 1, 14, 0, 0, 0, time.UTC),
+			wantQuiet: true,
+			want:      time.Date(2023, 6, 1, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "end of window is not quiet",
+			settings: day,
+			now:      time.Date(2023, 6, 1, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "across midnight before midnight",
+			settings:  night,
+			now:       time.Date(2023, 6, 1, 21, 0, 0, 0, time.UTC), // 23:00 в Берлине
+			wantQuiet: true,
+			want:      time.Date(2023, 6, 2, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "across midnight after midnight",
+			settings:  night,
+			now:       time.Date(2023, 6, 1, 23, 30, 0, 0, time.UTC), // 01:30 в Берлине
+			wantQuiet: true,
+			want:      time.Date(2023, 6, 2, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "across midnight outside window",
+			settings: night,
+			now:      time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "dst start during window",
+			settings:  night,
+			now:       time.Date(2023, 3, 25, 22, 0, 0, 0, time.UTC), // 23:00 CET, ночью часы переводятся вперед
+			wantQuiet: true,
+			want:      time.Date(2023, 3, 26, 5, 0, 0, 0, time.UTC), // 07:00 CEST
+		},
+		{
+			name:      "dst end during window",
+			settings:  night,
+			now:       time.Date(2023, 10, 28, 21, 0, 0, 0, time.UTC), // 23:00 CEST, ночью часы переводятся назад
+			wantQuiet: true,
+			want:      time.Date(2023, 10, 29, 6, 0, 0, 0, time.UTC), // 07:00 CET
+		},
+		{
+			name:      "repeated hour after dst end",
+			settings:  night,
+			now:       time.Date(2023, 10, 29, 1, 30, 0, 0, time.UTC), // 02:30 CET, второй раз за ночь
+			wantQuiet: true,
+			want:      time.Date(2023, 10, 29, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "quiet hours are not set",
+			settings: NotificationSettings{Timezone: "UTC"},
+			now:      time.Date(2023, 6, 1, 3, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			until, quiet := c.settings.QuietUntil(c.now)
+			if quiet != c.wantQuiet {
+				t.Fatalf("QuietUntil(%s) quiet = %v, want %v", c.now, quiet, c.wantQuiet)
+			}
+
+			if quiet && !until.Equal(c.want) {
+				t.Errorf("QuietUntil(%s) = %s, want %s", c.now, until, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+const PendingNotificationTableName = "sl_pending_notifications"
+
+// Событие по товару, которое ждет объединения с другими событиями или ежедневного дайджеста
+type PendingNotification struct {
+	ID               int64
+	TargetUserId     string
+	UserId           string
+	UserName         string
+	UserPhone        int64
+	ListId           string
+	ListName         string
+	ItemId           string
+	ItemName         string
+	TypeNotification NotificationType
+	Push             bool // Отправить push вместе с уведомлением
+	IsDigest         bool // Попадет в ежедневный дайджест
+	CreatedAt        int64
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+const PushJobTableName = "sl_push_jobs"
+const PushDeadLetterTableName = "sl_push_dead_letters"
+
+// Задание очереди отправки push одному пользователю
+type PushJob struct {
+	PushPayload
+	ID            int64      `json:"id"`
+	TargetUserId  string     `json:"target_user_id"`
+	Tokens        PushTokens `json:"tokens"` // Токены, которым осталось отправить. Пустое значение - все токены пользователя
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt int64      `json:"next_attempt_at"`
+	CreatedAt     int64      `json:"created_at"`
+	FailedAt      NullInt64  `json:"failed_at"` // Для заданий из dead letter
+	LockToken     string     `json:"-"`         // Токен захвата, с которым задание получил обработчик
+}
+
+// Список токенов задания, хранится в JSON
+type PushTokens []FCMToken
+
+func (s PushTokens) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	value, err := json.Marshal([]FCMToken(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(value), nil
+}
+
+func (s *PushTokens) Scan(value interface{}) error {
+	*s = nil
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+
+	return errors.New("unsupported type for PushTokens")
+}
+
+// Количество заданий в очереди по состоянию
+type PushQueueStats struct {
+	Pending  int `json:"pending"`  // Ждут первой отправки
+	Retrying int `json:"retrying"` // Ждут повторной отправки
+	Dead     int `json:"dead"`     // В dead letter
+}
@@ -0,0 +1,57 @@
+package models
+
+// Содержимое push, сохраненное в базе: отложенные push и задания очереди отправки
+type PushPayload struct {
+	TypeNotification string
+	Message          string
+	UserId           string
+	UserPhone        string
+	ListId           string
+	ItemId           string
+	Silent           bool // Без звука и баннера, push в тихие часы
+}
+
+// Уведомление, из которого строится push. Ему соответствуют формы и модели уведомлений
+type PushSource interface {
+	GetTypeNotification() string
+	GetUserPhone() string
+	GetUserId() string
+	GetListId() string
+	GetItemId() string
+	GetMessage() string
+}
+
+func NewPushPayload(source PushSource) PushPayload {
+	return PushPayload{
+		TypeNotification: source.GetTypeNotification(),
+		Message:          source.GetMessage(),
+		UserId:           source.GetUserId(),
+		UserPhone:        source.GetUserPhone(),
+		ListId:           source.GetListId(),
+		ItemId:           source.GetItemId(),
+	}
+}
+
+func (s PushPayload) GetTypeNotification() string {
+	return s.TypeNotification
+}
+
+func (s PushPayload) GetUserPhone() string {
+	return s.UserPhone
+}
+
+func (s PushPayload) GetUserId() string {
+	return s.UserId
+}
+
+func (s PushPayload) GetListId() string {
+	return s.ListId
+}
+
+func (s PushPayload) GetItemId() string {
+	return s.ItemId
+}
+
+func (s PushPayload) GetMessage() string {
+	return s.Message
+}
@@ -4,10 +4,15 @@ import "errors"
 
 const RefbookCategoryTableName = "sl_categories"
 const RefbookProductTableName = "sl_products"
+const RefbookSynonymTableName = "sl_product_synonyms"
+const RefbookBarcodeTableName = "sl_product_barcodes"
+const RefbookCategoryTranslationTableName = "sl_category_translations"
+const RefbookProductTranslationTableName = "sl_product_translations"
 
 type RefbookCategory struct {
 	ID        int64  `json:"id"`
 	Title     string `json:"title"`
+	IsDeleted bool   `json:"is_deleted"`
 	CreatedAt int64  `json:"created_at"`
 	UpdatedAt int64  `json:"updated_at"`
 }
@@ -21,11 +26,14 @@ func (s *RefbookCategory) Validate() (bool, error) {
 }
 
 type RefbookProduct struct {
-	ID         int64  `json:"id"`
-	Title      string `json:"title"`
-	CategoryId int64  `json:"category_id"`
-	CreatedAt  int64  `json:"created_at"`
-	UpdatedAt  int64  `json:"updated_at"`
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	CategoryId  int64    `json:"category_id"`
+	DefaultUnit string   `json:"default_unit"`
+	Synonyms    []string `json:"synonyms,omitempty"` // Заполняется только там, где нужен поиск по синонимам
+	IsDeleted   bool     `json:"is_deleted"`
+	CreatedAt   int64    `json:"created_at"`
+	UpdatedAt   int64    `json:"updated_at"`
 }
 
 func (s *RefbookProduct) Validate() (bool, error) {
@@ -39,3 +47,9 @@ func (s *RefbookProduct) Validate() (bool, error) {
 
 	return true, nil
 }
+
+// Состояние таблицы справочника, по нему вычисляется версия для клиентов
+type RefbookTableState struct {
+	LastUpdatedAt int64
+	Count         int64
+}
@@ -0,0 +1,28 @@
+package models
+
+// Итоги списка в одной валюте
+type ListTotal struct {
+	Currency    string  `json:"currency"`
+	Planned     float64 `json:"planned"` // Сумма цен всех неудаленных товаров
+	Actual      float64 `json:"actual"`  // Сумма цен отмеченных товаров
+	ItemsCount  int     `json:"items_count"`
+	MarkedCount int     `json:"marked_count"`
+}
+
+// Траты участника, отметившего товары, в одной валюте
+type MemberSpend struct {
+	UserID     string  `json:"user_id"`
+	Currency   string  `json:"currency"`
+	Actual     float64 `json:"actual"`
+	ItemsCount int     `json:"items_count"`
+}
+
+// Траты по списку за период
+type ListSpend struct {
+	ListID     string  `json:"list_id"`
+	ListName   string  `json:"list_name"`
+	UserID     string  `json:"user_id"`
+	Currency   string  `json:"currency"`
+	Actual     float64 `json:"actual"`
+	ItemsCount int     `json:"items_count"`
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"sort"
+	"strings"
+)
+
+const StoreTableName = "sl_stores"
+
+const maxStoreCategories = 500
+
+// Магазин пользователя с порядком категорий справочника, в котором они расположены в торговом зале
+type Store struct {
+	ID            string    `json:"id" valid:"uuid,required"`
+	OwnerID       string    `json:"owner_id" valid:"uuid,required"`
+	Name          string    `json:"name" valid:"stringlength(1|100),required"`
+	CategoryOrder Int64List `json:"category_order"`
+	CreatedAt     int64     `json:"created_at" valid:"int,required"`
+	UpdatedAt     int64     `json:"updated_at" valid:"int,required"`
+	ReceivedAt    int64     `json:"received_at"`
+	IsDeleted     bool      `json:"is_deleted"`
+}
+
+// Товары одной категории. Для товаров без категории CategoryID пустой
+type ItemsGroup struct {
+	CategoryID NullInt64  `json:"category_id"`
+	Items      []ListItem `json:"items"`
+}
+
+func (s *Store) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	if len(s.CategoryOrder) > maxStoreCategories {
+		return false, errors.New("too many categories in category_order")
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range s.CategoryOrder {
+		if id <= 0 {
+			return false, errors.New("category_order contains wrong category id")
+		}
+
+		if seen[id] {
+			return false, errors.New("category_order contains duplicate category id")
+		}
+
+		seen[id] = true
+	}
+
+	return true, nil
+}
+
+func (s *Store) IsEqual(store *Store) bool {
+	if len(s.CategoryOrder) != len(store.CategoryOrder) {
+		return false
+	}
+
+	for i := range s.CategoryOrder {
+		if s.CategoryOrder[i] != store.CategoryOrder[i] {
+			return false
+		}
+	}
+
+	return s.ID == store.ID &&
+		s.OwnerID == store.OwnerID &&
+		s.Name == store.Name &&
+		s.UpdatedAt == store.UpdatedAt &&
+		s.IsDeleted == store.IsDeleted
+}
+
+// Сгруппировать неудаленные товары по категориям в порядке обхода магазина.
+// Категории, которых нет в порядке магазина, идут следом по возрастанию ID, товары без категории - в конце.
+// Внутри группы сначала неотмеченные товары, затем по названию
+func (s *Store) GroupItems(items []ListItem) []ItemsGroup {
+	positions := make(map[int64]int)
+	for i, id := range s.CategoryOrder {
+		positions[id] = i
+	}
+
+	groupIndexes := make(map[NullInt64]int)
+	groups := make([]ItemsGroup, 0)
+
+	for _, item := range items {
+		if item.IsDeleted {
+			continue
+		}
+
+		key := item.CategoryID
+		if !key.Valid {
+			key = NullInt64{}
+		}
+
+		ind, ok := groupIndexes[key]
+		if !ok {
+			ind = len(groups)
+			groupIndexes[key] = ind
+			groups = append(groups, ItemsGroup{CategoryID: key})
+		}
+
+		groups[ind].Items = append(groups[ind].Items, item)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].CategoryID, groups[j].CategoryID
+		if a.Valid != b.Valid {
+			return a.Valid
+		}
+
+		posA, okA := positions[a.Int64]
+		posB, okB := positions[b.Int64]
+		if okA != okB {
+			return okA
+		}
+
+		if okA {
+			return posA < posB
+		}
+
+		return a.Int64 < b.Int64
+	})
+
+	for _, group := range groups {
+		groupItems := group.Items
+		sort.SliceStable(groupItems, func(i, j int) bool {
+			if groupItems[i].IsMarked != groupItems[j].IsMarked {
+				return !groupItems[i].IsMarked
+			}
+
+			return strings.ToLower(groupItems[i].Name) < strings.ToLower(groupItems[j].Name)
+		})
+	}
+
+	return groups
+}
@@ -0,0 +1,45 @@
+package models
+
+const SuggestionProductsTableName = "sl_suggestion_products"
+const SuggestionPairsTableName = "sl_suggestion_pairs"
+
+const (
+	SuggestionReasonInterval = "interval" // Пора купить снова по среднему интервалу покупок
+	SuggestionReasonTogether = "together" // Часто покупается вместе с товаром из списка
+	SuggestionReasonFavorite = "favorite" // Избранный товар пользователя
+)
+
+// Статистика покупок товара пользователем, пересчитывается пакетной задачей
+type ProductStat struct {
+	UserID         string    `json:"user_id"`
+	ProductKey     string    `json:"product_key"`
+	Name           string    `json:"name"`
+	ProductID      NullInt64 `json:"product_id"`
+	CategoryID     NullInt64 `json:"category_id"`
+	PurchasesCount int       `json:"purchases_count"`
+	IntervalDays   float64   `json:"interval_days"` // Медианный интервал между покупками, 0 - недостаточно данных
+	LastBoughtAt   int64     `json:"last_bought_at"`
+}
+
+// Пара товаров, которые покупаются вместе: при покупке ProductKey в Confidence случаев покупается RelatedKey
+type ProductPair struct {
+	UserID      string  `json:"user_id"`
+	ProductKey  string  `json:"product_key"`
+	RelatedKey  string  `json:"related_key"`
+	RelatedName string  `json:"related_name"`
+	Support     int     `json:"support"`
+	Confidence  float64 `json:"confidence"`
+}
+
+// Подсказка товара для списка
+type Suggestion struct {
+	ProductKey   string    `json:"product_key"`
+	Name         string    `json:"name"`
+	ProductID    NullInt64 `json:"product_id"`
+	CategoryID   NullInt64 `json:"category_id"`
+	Score        float64   `json:"score"`
+	Reason       string    `json:"reason"`
+	IntervalDays float64   `json:"interval_days,omitempty"`
+	DaysSince    float64   `json:"days_since,omitempty"`
+	RelatedName  string    `json:"related_name,omitempty"` // Товар из списка, с которым часто покупается подсказка
+}
@@ -7,10 +7,12 @@ import (
 
 const FCMTokenPlatformIOS = "ios"
 const FCMTokenPlatformAndriod = "android"
+const FCMTokenPlatformWeb = "web" // Подписка Web Push, токен - endpoint подписки
 
 type FCMToken struct {
-	Token    string `json:"token" valid:"stringlength(10|255)"`
-	Platform string `json:"platform" valid:"stringlength(2|10)"`
+	Token    string       `json:"token" valid:"stringlength(10|255)"`
+	Platform string       `json:"platform" valid:"stringlength(2|10)"`
+	Keys     *WebPushKeys `json:"keys,omitempty" valid:"-"` // Ключи шифрования, только для Web Push
 }
 
 func (s *FCMToken) Validate() (bool, error) {
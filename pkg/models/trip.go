@@ -0,0 +1,86 @@
+package models
+
+import (
+	"github.com/asaskevich/govalidator"
+)
+
+const TripTableName = "sl_trips"
+const PurchaseTableName = "sl_purchases"
+
+const (
+	TripStatusActive    = "active"
+	TripStatusFinished  = "finished"
+	TripStatusCancelled = "cancelled"
+)
+
+// Что сделать с купленными товарами после завершения похода в магазин
+const (
+	TripFinishKeep    = "keep"    // Оставить отмеченными
+	TripFinishClear   = "clear"   // Удалить из списка
+	TripFinishArchive = "archive" // Снять отметку и цену, чтобы переиспользовать список. Покупка остается в истории
+)
+
+// Поход в магазин по списку
+type Trip struct {
+	ID         string     `json:"id" valid:"uuid,required"`
+	ListID     string     `json:"list_id" valid:"uuid,required"`
+	UserID     string     `json:"user_id" valid:"uuid,required"`
+	StoreID    NullString `json:"store_id"`
+	Status     string     `json:"status" valid:"in(active|finished|cancelled),required"`
+	StartedAt  int64      `json:"started_at"`
+	FinishedAt int64      `json:"finished_at"`
+}
+
+func (s *Trip) Validate() (bool, error) {
+	_, err := govalidator.ValidateStruct(s)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Запись истории покупок. Не изменяется после создания
+type Purchase struct {
+	ID            string      `json:"id"`
+	TripID        string      `json:"trip_id"`
+	ListID        string      `json:"list_id"`
+	ItemID        string      `json:"item_id"`
+	UserID        string      `json:"user_id"` // Кто купил
+	StoreID       NullString  `json:"store_id"`
+	Name          string      `json:"name"`
+	Quantity      NullFloat64 `json:"quantity"`
+	Unit          string      `json:"unit"`
+	Price         NullFloat64 `json:"price"`
+	Currency      string      `json:"currency"`
+	ProductID     NullInt64   `json:"product_id"`
+	CategoryID    NullInt64   `json:"category_id"`
+	ItemUpdatedAt int64       `json:"-"` // Версия товара, по которой записана покупка
+	PurchasedAt   int64       `json:"purchased_at"`
+}
+
+// Создать запись истории из отмеченного товара
+func NewPurchase(id string, trip *Trip, item *ListItem, purchasedAt int64) Purchase {
+	userId := item.UserMarked.String
+	if item.UserMarked.IsEmpty() {
+		userId = trip.UserID
+	}
+
+	return Purchase{
+		ID:            id,
+		TripID:        trip.ID,
+		ListID:        item.ListID,
+		ItemID:        item.ID,
+		UserID:        userId,
+		StoreID:       trip.StoreID,
+		Name:          item.Name,
+		Quantity:      item.Quantity,
+		Unit:          item.Unit,
+		Price:         item.Price,
+		Currency:      item.Currency,
+		ProductID:     item.ProductID,
+		CategoryID:    item.CategoryID,
+		ItemUpdatedAt: item.UpdatedAt,
+		PurchasedAt:   purchasedAt,
+	}
+}
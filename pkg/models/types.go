@@ -84,3 +84,125 @@ func (ns *NullString) UnmarshalJSON(b []byte) error {
 	ns.Valid = (err == nil)
 	return err
 }
+
+type NullFloat64 sql.NullFloat64
+
+func NewNullFloat64(value float64) NullFloat64 {
+	return NullFloat64{Float64: value, Valid: true}
+}
+
+func (nf *NullFloat64) SqlValue() interface{} {
+	if nf.Valid {
+		return nf.Float64
+	}
+
+	return nil
+}
+
+func (nf *NullFloat64) Scan(value interface{}) error {
+	var f sql.NullFloat64
+	if err := f.Scan(value); err != nil {
+		return err
+	}
+
+	*nf = NullFloat64(f)
+
+	return nil
+}
+
+// MarshalJSON for NullFloat64
+func (nf NullFloat64) MarshalJSON() ([]byte, error) {
+	if !nf.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(nf.Float64)
+}
+
+// UnmarshalJSON for NullFloat64
+func (nf *NullFloat64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*nf = NullFloat64{}
+		return nil
+	}
+
+	err := json.Unmarshal(b, &nf.Float64)
+	nf.Valid = (err == nil)
+	return err
+}
+
+type NullInt64 sql.NullInt64
+
+func NewNullInt64(value int64) NullInt64 {
+	return NullInt64{Int64: value, Valid: true}
+}
+
+func (ni *NullInt64) SqlValue() interface{} {
+	if ni.Valid {
+		return ni.Int64
+	}
+
+	return nil
+}
+
+func (ni *NullInt64) Scan(value interface{}) error {
+	var i sql.NullInt64
+	if err := i.Scan(value); err != nil {
+		return err
+	}
+
+	*ni = NullInt64(i)
+
+	return nil
+}
+
+// MarshalJSON for NullInt64
+func (ni NullInt64) MarshalJSON() ([]byte, error) {
+	if !ni.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(ni.Int64)
+}
+
+// UnmarshalJSON for NullInt64
+func (ni *NullInt64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*ni = NullInt64{}
+		return nil
+	}
+
+	err := json.Unmarshal(b, &ni.Int64)
+	ni.Valid = (err == nil)
+	return err
+}
+
+// Список чисел, хранится в БД как JSON-массив
+type Int64List []int64
+
+func (l Int64List) SqlValue() interface{} {
+	if l == nil {
+		return "[]"
+	}
+
+	b, err := json.Marshal([]int64(l))
+	if err != nil {
+		return "[]"
+	}
+
+	return string(b)
+}
+
+func (l *Int64List) Scan(value interface{}) error {
+	var s sql.NullString
+	if err := s.Scan(value); err != nil {
+		return err
+	}
+
+	if !s.Valid || s.String == "" {
+		*l = Int64List{}
+		return nil
+	}
+
+	return json.Unmarshal([]byte(s.String), (*[]int64)(l))
+}
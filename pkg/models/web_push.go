@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"net/url"
+	"strings"
+)
+
+const WebPushSubscriptionTableName = "sl_web_push_subscriptions"
+
+// Push-сервисы браузеров. Запросы на другие адреса не отправляются, чтобы подписка не открывала доступ во внутреннюю сеть
+var webPushHosts = []string{
+	"fcm.googleapis.com",        // Chrome, Edge на Android, Opera
+	"push.services.mozilla.com", // Firefox
+	"push.apple.com",            // Safari
+	"notify.windows.com",        // Edge на Windows
+}
+
+// Подписка браузера в формате PushSubscription.toJSON()
+type WebPushSubscription struct {
+	Endpoint string      `json:"endpoint"`
+	Keys     WebPushKeys `json:"keys"`
+}
+
+// Ключи подписки в base64url: открытый ключ P-256 браузера и секрет аутентификации
+type WebPushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+func (s *WebPushSubscription) Validate() (bool, error) {
+	if !strings.HasPrefix(s.Endpoint, "https://") || !govalidator.IsURL(s.Endpoint) || len(s.Endpoint) > 1024 {
+		return false, errors.New("endpoint must be https url")
+	}
+
+	if !IsWebPushEndpointAllowed(s.Endpoint) {
+		return false, errors.New("endpoint is not a known push service")
+	}
+
+	if key, err := DecodeWebPushKey(s.Keys.P256dh); err != nil || len(key) != 65 || key[0] != 4 {
+		return false, errors.New("p256dh key is wrong")
+	}
+
+	if secret, err := DecodeWebPushKey(s.Keys.Auth); err != nil || len(secret) != 16 {
+		return false, errors.New("auth secret is wrong")
+	}
+
+	return true, nil
+}
+
+// Endpoint ведет на известный push-сервис по https на стандартном порту.
+// IP-адреса, в том числе локальные и внутренние, не проходят проверку
+func IsWebPushEndpointAllowed(endpoint string) bool {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme != "https" || parsed.User != nil || (parsed.Port() != "" && parsed.Port() != "443") {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range webPushHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Подписка как токен для очереди отправки push
+func (s WebPushSubscription) Token() FCMToken {
+	keys := s.Keys
+
+	return FCMToken{Token: s.Endpoint, Platform: FCMTokenPlatformWeb, Keys: &keys}
+}
+
+// Браузеры отдают ключи в base64url, обычно без выравнивания
+func DecodeWebPushKey(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
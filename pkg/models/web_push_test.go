@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestIsWebPushEndpointAllowed(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		want     bool
+	}{
+		{"https://fcm.googleapis.com/fcm/send/abc:def", true},
+		{"https://updates.push.services.mozilla.com/wpush/v2/abc", true},
+		{"https://web.push.apple.com/QGuQ", true},
+		{"https://wns2-db5p.notify.windows.com/w/?token=abc", true},
+		{"https://FCM.googleapis.com:443/fcm/send/abc", true},
+		{"http://fcm.googleapis.com/fcm/send/abc", false},
+		{"https://fcm.googleapis.com:8443/fcm/send/abc", false},
+		{"https://user@fcm.googleapis.com/fcm/send/abc", false},
+		{"https://evilfcm.googleapis.com.example.com/abc", false},
+		{"https://notpush.apple.com/abc", false},
+		{"https://127.0.0.1/abc", false},
+		{"https://10.0.0.5/abc", false},
+		{"https://[::1]/abc", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://localhost/abc", false},
+	}
+
+	for _, c := range cases {
+		if got := IsWebPushEndpointAllowed(c.endpoint); got != c.want {
+			t.Errorf("IsWebPushEndpointAllowed(%s) = %v, want %v", c.endpoint, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package notifications
+
+import "shopingList/pkg/models"
+
+// События одного автора в одном списке для одного получателя
+type Group struct {
+	TargetUserId string
+	Events       []models.PendingNotification
+}
+
+// Сгруппировать события по получателю, автору и списку в порядке появления
+func GroupPending(events []models.PendingNotification) []Group {
+	groups := make([]Group, 0)
+	index := make(map[string]int)
+
+	for _, event := range events {
+		key := event.TargetUserId + "|" + event.UserId + "|" + event.ListId
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{TargetUserId: event.TargetUserId})
+		}
+
+		groups[i].Events = append(groups[i].Events, event)
+	}
+
+	return groups
+}
+
+// Сгруппировать события дайджеста по получателю
+func GroupDigest(events []models.PendingNotification) []Group {
+	groups := make([]Group, 0)
+	index := make(map[string]int)
+
+	for _, event := range events {
+		i, ok := index[event.TargetUserId]
+		if !ok {
+			i = len(groups)
+			index[event.TargetUserId] = i
+			groups = append(groups, Group{TargetUserId: event.TargetUserId})
+		}
+
+		groups[i].Events = append(groups[i].Events, event)
+	}
+
+	return groups
+}
+
+func (s *Group) IDs() []int64 {
+	ids := make([]int64, 0, len(s.Events))
+	for _, event := range s.Events {
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+// Нужно ли отправить push. Берется настройка последнего события
+func (s *Group) Push() bool {
+	return len(s.Events) > 0 && s.Events[len(s.Events)-1].Push
+}
+
+// Уведомление по группе событий одного автора в одном списке.
+// Одно событие выглядит как обычное уведомление, несколько - как сводка по количеству товаров
+func (s *Group) Form() models.NotificationCreateForm {
+	last := s.Events[len(s.Events)-1]
+
+	form := models.NotificationCreateForm{
+		TypeNotification: last.TypeNotification,
+		UserId:           last.UserId,
+		UserPhone:        last.UserPhone,
+		ListId:           last.ListId,
+		TargetUserId:     s.TargetUserId,
+		Params:           models.NotificationParams{ActorName: last.UserName, List: last.ListName},
+	}
+
+	items := make(map[string]bool)
+	sameType := true
+
+	for _, event := range s.Events {
+		items[event.ItemId] = true
+		if event.TypeNotification != last.TypeNotification {
+			sameType = false
+		}
+	}
+
+	switch {
+	case len(s.Events) == 1:
+		form.Template = GoodsTemplate(last.TypeNotification)
+		form.Params.Item = last.ItemName
+	case sameType:
+		form.Template = GoodsGroupTemplate(last.TypeNotification)
+		form.Params.Count = len(items)
+	default:
+		form.TypeNotification = models.NotificationTypeGoodsChange
+		form.Template = TemplateGoodsChanges
+		form.Params.Count = len(s.Events)
+	}
+
+	if len(items) == 1 {
+		form.ItemId = models.NullString{String: last.ItemId, Valid: true}
+	}
+
+	Fill(&form)
+
+	return form
+}
+
+// Уведомление-дайджест по всем спискам получателя
+func (s *Group) DigestForm() models.NotificationCreateForm {
+	form := models.NotificationCreateForm{
+		TypeNotification: models.NotificationTypeGoodsDigest,
+		Template:         TemplateGoodsDigest,
+		TargetUserId:     s.TargetUserId,
+	}
+
+	var listIds []string
+	listNames := make(map[string]string)
+	listCounts := make(map[string]int)
+	actors := make(map[string]bool)
+
+	for _, event := range s.Events {
+		if _, ok := listCounts[event.ListId]; !ok {
+			listIds = append(listIds, event.ListId)
+		}
+
+		listCounts[event.ListId]++
+		listNames[event.ListId] = event.ListName
+		actors[event.UserId] = true
+	}
+
+	if len(listIds) == 1 {
+		form.ListId = listIds[0]
+	}
+
+	if len(actors) == 1 {
+		last := s.Events[len(s.Events)-1]
+		form.UserId = last.UserId
+		form.UserPhone = last.UserPhone
+		form.Params.ActorName = last.UserName
+	}
+
+	for _, listId := range listIds {
+		form.Params.Lists = append(form.Params.Lists, models.NotificationListCount{Name: listNames[listId], Count: listCounts[listId]})
+	}
+
+	Fill(&form)
+
+	return form
+}
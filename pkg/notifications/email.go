@@ -0,0 +1,183 @@
+package notifications
+
+import (
+	log "github.com/sirupsen/logrus"
+	htmlTemplate "html/template"
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/services/email"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	textTemplate "text/template"
+	"time"
+)
+
+// Сколько ждать места в очереди писем. Дольше ждать нельзя, чтобы медленный SMTP не останавливал listeners
+const emailQueueTimeout = 5 * time.Second
+
+var notificationText = textTemplate.Must(textTemplate.New("notification").Parse(
+	`{{.Message}}
+
+--
+{{.Footer}}
+`))
+
+var notificationHTML = htmlTemplate.Must(htmlTemplate.New("notification").Parse(
+	`<!DOCTYPE html>
+<html lang="{{.Locale}}">
+<body style="font-family: Arial, sans-serif; color: #222;">
+<p style="font-size: 16px;">{{.Message}}</p>
+<hr style="border: none; border-top: 1px solid #ddd;">
+<p style="font-size: 12px; color: #888;">{{.Footer}}</p>
+</body>
+</html>
+`))
+
+var weeklyText = textTemplate.Must(textTemplate.New("weekly").Parse(
+	`{{.Greeting}}
+
+{{.Intro}}
+{{range .Lists}}
+- {{.Name}}: {{.Summary}}{{end}}
+
+--
+{{.Footer}}
+`))
+
+var weeklyHTML = htmlTemplate.Must(htmlTemplate.New("weekly").Parse(
+	`<!DOCTYPE html>
+<html lang="{{.Locale}}">
+<body style="font-family: Arial, sans-serif; color: #222;">
+<p style="font-size: 16px;">{{.Greeting}}</p>
+<p>{{.Intro}}</p>
+<ul>
+{{range .Lists}}<li><b>{{.Name}}</b>: {{.Summary}}</li>
+{{end}}</ul>
+<hr style="border: none; border-top: 1px solid #ddd;">
+<p style="font-size: 12px; color: #888;">{{.Footer}}</p>
+</body>
+</html>
+`))
+
+type weeklyList struct {
+	Name    string
+	Summary string
+}
+
+// Письмо с текстом уведомления, текст уже на языке получателя
+func NotificationEmail(recipient models.EmailRecipient, message string) email.Message {
+	locale := emailLocale(recipient.Locale)
+	data := struct {
+		Locale  string
+		Message string
+		Footer  string
+	}{locale, message, lookup(templateEmailFooter, locale)}
+
+	return buildEmail(recipient, message, data, notificationText, notificationHTML)
+}
+
+// Еженедельная сводка по спискам
+func WeeklySummaryEmail(recipient models.EmailRecipient, activity []models.ListActivity) email.Message {
+	locale := emailLocale(recipient.Locale)
+
+	lists := make([]weeklyList, 0, len(activity))
+	for _, list := range activity {
+		summary := format(lookup(templateWeeklyList, locale), locale,
+			map[string]string{"added": strconv.Itoa(list.Added), "purchased": strconv.Itoa(list.Purchased)},
+			map[string]int{"added": list.Added, "purchased": list.Purchased})
+		lists = append(lists, weeklyList{Name: list.Name, Summary: summary})
+	}
+
+	name := recipient.Name
+	if name == "" {
+		name = recipient.Email
+	}
+
+	data := struct {
+		Locale   string
+		Greeting string
+		Intro    string
+		Lists    []weeklyList
+		Footer   string
+	}{
+		Locale:   locale,
+		Greeting: format(lookup(templateWeeklyGreeting, locale), locale, map[string]string{"name": name}, nil),
+		Intro:    lookup(templateWeeklyIntro, locale),
+		Lists:    lists,
+		Footer:   lookup(templateWeeklyFooter, locale),
+	}
+
+	return buildEmail(recipient, lookup(templateWeeklySubject, locale), data, weeklyText, weeklyHTML)
+}
+
+func buildEmail(recipient models.EmailRecipient, subject string, data interface{}, text *textTemplate.Template, html *htmlTemplate.Template) email.Message {
+	var textBody, htmlBody strings.Builder
+
+	if err := text.Execute(&textBody, data); err != nil {
+		log.Errorln("Error render email text; " + err.Error())
+	}
+
+	if err := html.Execute(&htmlBody, data); err != nil {
+		log.Errorln("Error render email html; " + err.Error())
+	}
+
+	return email.Message{
+		To:      recipient.Email,
+		ToName:  recipient.Name,
+		Subject: subject,
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}
+}
+
+func emailLocale(locale string) string {
+	if !i18n.IsSupported(locale) {
+		return i18n.DefaultLocale
+	}
+
+	return locale
+}
+
+// Отправка уведомлений на email тем, кто подписался на этот тип.
+// Письма уходят в буферизованный канал, отправку выполняет EmailListener
+type EmailNotifier struct {
+	Preferences readModels.NotificationPreferencesReadRepository
+	Channel     chan email.Message
+	dropped     int64 // Сколько писем не попало в очередь за время работы
+}
+
+// Отправить письма получателям. messages - тексты уведомлений по получателям
+func (s *EmailNotifier) Notify(typeNotification models.NotificationType, userIds []string, messages map[string]string) {
+	if s == nil || s.Channel == nil || len(userIds) == 0 {
+		return
+	}
+
+	recipients, err := s.Preferences.GetEmailRecipients(typeNotification, userIds)
+	if err != nil {
+		log.Errorln("Error get email recipients; " + err.Error())
+		return
+	}
+
+	for _, recipient := range recipients {
+		if message := messages[recipient.UserId]; message != "" {
+			s.enqueue(recipient.UserId, NotificationEmail(recipient, message))
+		}
+	}
+}
+
+// Поставить письмо в очередь. При заполненном буфере ждать освобождения не дольше emailQueueTimeout,
+// иначе письмо пропускается и учитывается в счетчике
+func (s *EmailNotifier) enqueue(userId string, message email.Message) {
+	timer := time.NewTimer(emailQueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case s.Channel <- message:
+	case <-timer.C:
+		dropped := atomic.AddInt64(&s.dropped, 1)
+		log.Errorln("Email queue is full, skip email for user " + userId +
+			"; dropped emails: " + strconv.FormatInt(dropped, 10))
+	}
+}
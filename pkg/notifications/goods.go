@@ -0,0 +1,39 @@
+package notifications
+
+import "shopingList/pkg/models"
+
+// Шаблон уведомления об одном изменении товара
+func GoodsTemplate(typeNotification models.NotificationType) string {
+	switch typeNotification {
+	case models.NotificationTypeGoodsCreate:
+		return TemplateGoodsCreate
+	case models.NotificationTypeGoodsChange:
+		return TemplateGoodsChange
+	case models.NotificationTypeGoodsCheck:
+		return TemplateGoodsCheck
+	case models.NotificationTypeGoodsUncheck:
+		return TemplateGoodsUncheck
+	case models.NotificationTypeGoodsDelete:
+		return TemplateGoodsDelete
+	}
+
+	return ""
+}
+
+// Шаблон уведомления о нескольких изменениях товаров одного типа в одном списке
+func GoodsGroupTemplate(typeNotification models.NotificationType) string {
+	switch typeNotification {
+	case models.NotificationTypeGoodsCreate:
+		return TemplateGoodsCreateGroup
+	case models.NotificationTypeGoodsChange:
+		return TemplateGoodsChangeGroup
+	case models.NotificationTypeGoodsCheck:
+		return TemplateGoodsCheckGroup
+	case models.NotificationTypeGoodsUncheck:
+		return TemplateGoodsUncheckGroup
+	case models.NotificationTypeGoodsDelete:
+		return TemplateGoodsDeleteGroup
+	}
+
+	return TemplateGoodsChanges
+}
@@ -0,0 +1,104 @@
+package notifications
+
+import (
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+)
+
+// Построение текстов уведомлений с учетом языка получателя и того, как он называет автора
+type Renderer struct {
+	Settings readModels.NotificationPreferencesReadRepository
+	Aliases  readModels.ContactAliasesReadRepository
+}
+
+// Заполнить текст на языке по умолчанию. Он сохраняется, если у получателя нет настроек
+func Fill(form *models.NotificationCreateForm) {
+	form.Message = Render(form.Template, i18n.DefaultLocale, form.Params, "")
+}
+
+// Вернуть текст уведомления для каждого получателя.
+// Если рендер не задан или настройки не удалось получить, используется текст на языке по умолчанию
+func (s *Renderer) Messages(form models.NotificationCreateForm, targetIds []string) map[string]string {
+	locales := make(map[string]string)
+	aliases := make(map[string]string)
+
+	if s != nil {
+		settings, err := s.Settings.GetSettingsForUsers(targetIds)
+		if err != nil {
+			log.Errorln("Error get notification settings; " + err.Error())
+		}
+
+		for userId, userSettings := range settings {
+			locales[userId] = userSettings.Locale
+		}
+
+		aliases, err = s.Aliases.GetAliasesOf(form.UserId, targetIds)
+		if err != nil {
+			log.Errorln("Error get contact aliases; " + err.Error())
+			aliases = make(map[string]string)
+		}
+	}
+
+	messages := make(map[string]string, len(targetIds))
+
+	for _, targetId := range targetIds {
+		locale, ok := locales[targetId]
+		if !ok {
+			locale = i18n.DefaultLocale
+		}
+
+		messages[targetId] = render(form.Message, form.Template, locale, form.Params, aliases[targetId])
+	}
+
+	return messages
+}
+
+// Построить заново тексты уведомлений пользователя на указанном языке. Если язык не указан, берется из настроек
+func (s *Renderer) RenderForUser(userId string, locale string, items []models.Notification) error {
+	aliases := make(map[string]string)
+
+	if s != nil {
+		if locale == "" {
+			settings, err := s.Settings.GetSettingsForUser(userId)
+			if err != nil {
+				return err
+			}
+
+			locale = settings.Locale
+		}
+
+		list, err := s.Aliases.GetForUser(userId)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range list {
+			aliases[alias.UserId] = alias.Alias
+		}
+	}
+
+	if locale == "" {
+		locale = i18n.DefaultLocale
+	}
+
+	for i := range items {
+		items[i].Message = render(items[i].Message, items[i].Template, locale, items[i].Params, aliases[items[i].UserId])
+	}
+
+	return nil
+}
+
+// Уведомления, созданные до появления шаблонов, остаются с сохраненным текстом
+func render(message string, template string, locale string, params models.NotificationParams, actor string) string {
+	if template == "" {
+		return message
+	}
+
+	if text := Render(template, locale, params, actor); text != "" {
+		return text
+	}
+
+	return message
+}
@@ -0,0 +1,331 @@
+package notifications
+
+import (
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"strconv"
+	"strings"
+)
+
+// Ключи шаблонов уведомлений
+const (
+	TemplateGoodsCreate       = "goods.create"
+	TemplateGoodsChange       = "goods.change"
+	TemplateGoodsCheck        = "goods.check"
+	TemplateGoodsUncheck      = "goods.uncheck"
+	TemplateGoodsDelete       = "goods.delete"
+	TemplateGoodsCreateGroup  = "goods.create.group"
+	TemplateGoodsChangeGroup  = "goods.change.group"
+	TemplateGoodsCheckGroup   = "goods.check.group"
+	TemplateGoodsUncheckGroup = "goods.uncheck.group"
+	TemplateGoodsDeleteGroup  = "goods.delete.group"
+	TemplateGoodsChanges      = "goods.changes"
+	TemplateGoodsDigest       = "goods.digest"
+	TemplateDigestMore        = "goods.digest.more"
+	TemplateListInvite        = "list.invite"
+	TemplateListAccept        = "list.accept"
+	TemplateListRefuse        = "list.refuse"
+	TemplateListShareDelete   = "list.share_delete"
+	TemplateListDelete        = "list.delete"
+	TemplateListRecurring     = "list.recurring"
+	TemplateBudgetList        = "budget.list"
+	TemplateBudgetMonth       = "budget.month"
+	templateUnknownActor      = "actor.unknown"
+	templateEmailFooter       = "email.footer"
+	templateWeeklySubject     = "email.weekly.subject"
+	templateWeeklyGreeting    = "email.weekly.greeting"
+	templateWeeklyIntro       = "email.weekly.intro"
+	templateWeeklyList        = "email.weekly.list"
+	templateWeeklyFooter      = "email.weekly.footer"
+)
+
+// Каталог шаблонов по ключу и языку.
+// {name} - значение параметра, {count|товар|товара|товаров} - форма слова для числа по правилам языка
+var catalog = map[string]map[string]string{
+	TemplateGoodsCreate: {
+		i18n.LocaleRu: `{actor} добавил товар "{item}" в список "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізіміне "{item}" тауарын қосты`,
+		i18n.LocaleEn: `{actor} added "{item}" to the list "{list}"`,
+	},
+	TemplateGoodsChange: {
+		i18n.LocaleRu: `{actor} изменил товар "{item}" из списка "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізіміндегі "{item}" тауарын өзгертті`,
+		i18n.LocaleEn: `{actor} changed "{item}" in the list "{list}"`,
+	},
+	TemplateGoodsCheck: {
+		i18n.LocaleRu: `{actor} отметил товар "{item}" из списка "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізіміндегі "{item}" тауарын белгіледі`,
+		i18n.LocaleEn: `{actor} checked off "{item}" in the list "{list}"`,
+	},
+	TemplateGoodsUncheck: {
+		i18n.LocaleRu: `{actor} снял отметку с товара "{item}" из списка "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізіміндегі "{item}" тауарының белгісін алып тастады`,
+		i18n.LocaleEn: `{actor} unchecked "{item}" in the list "{list}"`,
+	},
+	TemplateGoodsDelete: {
+		i18n.LocaleRu: `{actor} удалил товар "{item}" из списка "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізімінен "{item}" тауарын жойды`,
+		i18n.LocaleEn: `{actor} removed "{item}" from the list "{list}"`,
+	},
+	TemplateGoodsCreateGroup: {
+		i18n.LocaleRu: `{actor} добавил {count} {count|товар|товара|товаров} в список «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізіміне {count} тауар қосты`,
+		i18n.LocaleEn: `{actor} added {count} {count|item|items} to the list "{list}"`,
+	},
+	TemplateGoodsChangeGroup: {
+		i18n.LocaleRu: `{actor} изменил {count} {count|товар|товара|товаров} в списке «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізімінде {count} тауарды өзгертті`,
+		i18n.LocaleEn: `{actor} changed {count} {count|item|items} in the list "{list}"`,
+	},
+	TemplateGoodsCheckGroup: {
+		i18n.LocaleRu: `{actor} отметил {count} {count|товар|товара|товаров} в списке «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізімінде {count} тауарды белгіледі`,
+		i18n.LocaleEn: `{actor} checked off {count} {count|item|items} in the list "{list}"`,
+	},
+	TemplateGoodsUncheckGroup: {
+		i18n.LocaleRu: `{actor} снял отметку с {count} {count|товара|товаров|товаров} в списке «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізімінде {count} тауардың белгісін алып тастады`,
+		i18n.LocaleEn: `{actor} unchecked {count} {count|item|items} in the list "{list}"`,
+	},
+	TemplateGoodsDeleteGroup: {
+		i18n.LocaleRu: `{actor} удалил {count} {count|товар|товара|товаров} из списка «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізімінен {count} тауарды жойды`,
+		i18n.LocaleEn: `{actor} removed {count} {count|item|items} from the list "{list}"`,
+	},
+	TemplateGoodsChanges: {
+		i18n.LocaleRu: `{actor} внес {count} {count|изменение|изменения|изменений} в список «{list}»`,
+		i18n.LocaleKk: `{actor} «{list}» тізіміне {count} өзгеріс енгізді`,
+		i18n.LocaleEn: `{actor} made {count} {count|change|changes} to the list "{list}"`,
+	},
+	TemplateGoodsDigest: {
+		i18n.LocaleRu: `Изменения в списках за день: {lists}`,
+		i18n.LocaleKk: `Тізімдердегі бүгінгі өзгерістер: {lists}`,
+		i18n.LocaleEn: `Today's changes in your lists: {lists}`,
+	},
+	TemplateDigestMore: {
+		i18n.LocaleRu: `и еще {count}`,
+		i18n.LocaleKk: `және тағы {count}`,
+		i18n.LocaleEn: `and {count} more`,
+	},
+	TemplateListInvite: {
+		i18n.LocaleRu: `{actor} пригласил вас в список "{list}"`,
+		i18n.LocaleKk: `{actor} сізді "{list}" тізіміне шақырды`,
+		i18n.LocaleEn: `{actor} invited you to the list "{list}"`,
+	},
+	TemplateListAccept: {
+		i18n.LocaleRu: `{actor} присоединился к списку "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізіміне қосылды`,
+		i18n.LocaleEn: `{actor} joined the list "{list}"`,
+	},
+	TemplateListRefuse: {
+		i18n.LocaleRu: `{actor} покинул список "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізімінен шықты`,
+		i18n.LocaleEn: `{actor} left the list "{list}"`,
+	},
+	TemplateListShareDelete: {
+		i18n.LocaleRu: `{actor} отменил шаринг списка "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізімін бөлісуді тоқтатты`,
+		i18n.LocaleEn: `{actor} stopped sharing the list "{list}"`,
+	},
+	TemplateListDelete: {
+		i18n.LocaleRu: `{actor} удалил список "{list}"`,
+		i18n.LocaleKk: `{actor} "{list}" тізімін жойды`,
+		i18n.LocaleEn: `{actor} deleted the list "{list}"`,
+	},
+	TemplateListRecurring: {
+		i18n.LocaleRu: `По расписанию создан список "{list}"`,
+		i18n.LocaleKk: `Кесте бойынша "{list}" тізімі құрылды`,
+		i18n.LocaleEn: `The list "{list}" was created on schedule`,
+	},
+	TemplateBudgetList: {
+		i18n.LocaleRu: `Превышен бюджет списка "{list}": {spent} из {budget} {currency}`,
+		i18n.LocaleKk: `"{list}" тізімінің бюджеті асып кетті: {spent} / {budget} {currency}`,
+		i18n.LocaleEn: `The budget of the list "{list}" is exceeded: {spent} of {budget} {currency}`,
+	},
+	TemplateBudgetMonth: {
+		i18n.LocaleRu: `Превышен месячный бюджет: {spent} из {budget} {currency}`,
+		i18n.LocaleKk: `Айлық бюджет асып кетті: {spent} / {budget} {currency}`,
+		i18n.LocaleEn: `Monthly budget exceeded: {spent} of {budget} {currency}`,
+	},
+	templateUnknownActor: {
+		i18n.LocaleRu: `Участник списка`,
+		i18n.LocaleKk: `Тізім қатысушысы`,
+		i18n.LocaleEn: `A list member`,
+	},
+	templateEmailFooter: {
+		i18n.LocaleRu: `Вы получили это письмо, потому что включили уведомления на email. Отключить их можно в настройках уведомлений в приложении.`,
+		i18n.LocaleKk: `Сіз бұл хатты email хабарламаларын қосқандықтан алдыңыз. Оларды қолданбадағы хабарлама баптауларынан өшіруге болады.`,
+		i18n.LocaleEn: `You received this email because you turned on email notifications. You can turn them off in the notification settings of the app.`,
+	},
+	templateWeeklySubject: {
+		i18n.LocaleRu: `Итоги недели в ваших списках`,
+		i18n.LocaleKk: `Тізімдеріңіздегі аптаның қорытындысы`,
+		i18n.LocaleEn: `Your week in shopping lists`,
+	},
+	templateWeeklyGreeting: {
+		i18n.LocaleRu: `Здравствуйте, {name}!`,
+		i18n.LocaleKk: `Сәлеметсіз бе, {name}!`,
+		i18n.LocaleEn: `Hello, {name}!`,
+	},
+	templateWeeklyIntro: {
+		i18n.LocaleRu: `Вот что происходило в ваших списках за неделю:`,
+		i18n.LocaleKk: `Апта ішінде тізімдеріңізде болған өзгерістер:`,
+		i18n.LocaleEn: `Here is what happened in your lists this week:`,
+	},
+	templateWeeklyList: {
+		i18n.LocaleRu: `добавлено {added} {added|товар|товара|товаров}, куплено {purchased}`,
+		i18n.LocaleKk: `{added} тауар қосылды, {purchased} сатып алынды`,
+		i18n.LocaleEn: `{added} {added|item|items} added, {purchased} bought`,
+	},
+	templateWeeklyFooter: {
+		i18n.LocaleRu: `Вы получили это письмо, потому что подписались на еженедельную сводку. Отписаться можно в настройках уведомлений в приложении.`,
+		i18n.LocaleKk: `Сіз бұл хатты апталық шолуға жазылғандықтан алдыңыз. Одан бас тартуды қолданбадағы хабарлама баптауларынан жасауға болады.`,
+		i18n.LocaleEn: `You received this email because you subscribed to the weekly summary. You can unsubscribe in the notification settings of the app.`,
+	},
+}
+
+// Сколько списков перечисляется в дайджесте, остальные показываются количеством
+const digestMaxLists = 5
+
+// Построить текст уведомления на языке получателя. actor - как получатель видит автора события.
+// Если перевода нет, используется язык по умолчанию, для неизвестного шаблона возвращается пустая строка
+func Render(template string, locale string, params models.NotificationParams, actor string) string {
+	if !i18n.IsSupported(locale) {
+		locale = i18n.DefaultLocale
+	}
+
+	text := lookup(template, locale)
+	if text == "" {
+		return ""
+	}
+
+	if actor == "" {
+		actor = ActorName(params.ActorName, locale)
+	}
+
+	values := map[string]string{
+		"actor":    actor,
+		"item":     params.Item,
+		"list":     params.List,
+		"count":    strconv.Itoa(params.Count),
+		"spent":    strconv.FormatFloat(params.Spent, 'f', 2, 64),
+		"budget":   strconv.FormatFloat(params.Budget, 'f', 2, 64),
+		"currency": params.Currency,
+		"lists":    renderLists(params.Lists, locale),
+	}
+
+	numbers := map[string]int{"count": params.Count}
+
+	return format(text, locale, values, numbers)
+}
+
+// Как показывать автора, если у получателя нет своего названия для него: имя, а если его нет - "участник списка"
+func ActorName(name string, locale string) string {
+	if name != "" {
+		return name
+	}
+
+	return lookup(templateUnknownActor, locale)
+}
+
+func lookup(template string, locale string) string {
+	texts, ok := catalog[template]
+	if !ok {
+		return ""
+	}
+
+	if text, ok := texts[locale]; ok {
+		return text
+	}
+
+	return texts[i18n.DefaultLocale]
+}
+
+func renderLists(lists []models.NotificationListCount, locale string) string {
+	parts := make([]string, 0, digestMaxLists+1)
+
+	for i, list := range lists {
+		if i == digestMaxLists {
+			more := models.NotificationParams{Count: len(lists) - digestMaxLists}
+			parts = append(parts, Render(TemplateDigestMore, locale, more, "-"))
+			break
+		}
+
+		parts = append(parts, "«"+list.Name+"» - "+strconv.Itoa(list.Count))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Подставить параметры в шаблон
+func format(text string, locale string, values map[string]string, numbers map[string]int) string {
+	var result strings.Builder
+
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		result.WriteString(text[:start])
+
+		fields := strings.Split(text[start+1:start+end], "|")
+		if len(fields) > 1 {
+			result.WriteString(pluralForm(locale, numbers[fields[0]], fields[1:]))
+		} else {
+			result.WriteString(values[fields[0]])
+		}
+
+		text = text[start+end+1:]
+	}
+
+	result.WriteString(text)
+
+	return result.String()
+}
+
+// Выбрать форму слова для числа. Для русского формы: один, несколько, много; для английского: один, много
+func pluralForm(locale string, n int, forms []string) string {
+	var i int
+
+	switch locale {
+	case i18n.LocaleRu:
+		i = pluralRu(n)
+	case i18n.LocaleEn:
+		if n != 1 {
+			i = 1
+		}
+	}
+
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+
+	return forms[i]
+}
+
+// 1 товар, 2 товара, 5 товаров
+func pluralRu(n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	n = n % 100
+	if n >= 11 && n <= 14 {
+		return 2
+	}
+
+	switch n % 10 {
+	case 1:
+		return 0
+	case 2, 3, 4:
+		return 1
+	}
+
+	return 2
+}
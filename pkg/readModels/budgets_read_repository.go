@@ -0,0 +1,88 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+type BudgetsReadRepository struct {
+	db *sql.DB
+}
+
+func NewBudgetsReadRepository(db *sql.DB) BudgetsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return BudgetsReadRepository{db: db}
+}
+
+// Вернуть бюджет пользователя. Для месячного бюджета listId пустой
+func (s *BudgetsReadRepository) Get(ownerId string, listId string) (models.Budget, error) {
+	row := s.db.QueryRow(s.getSelectPartSql()+` WHERE owner_id = ? AND list_id = ?`, ownerId, listId)
+
+	var budget models.Budget
+	if err := s.scan(row, &budget); err != nil {
+		if err == sql.ErrNoRows {
+			return models.Budget{}, repositories.ErrNotFound{}
+		}
+
+		return models.Budget{}, err
+	}
+
+	return budget, nil
+}
+
+// Вернуть неудаленный бюджет списка
+func (s *BudgetsReadRepository) GetActiveForList(listId string) (models.Budget, error) {
+	row := s.db.QueryRow(s.getSelectPartSql()+` WHERE list_id = ? AND is_deleted = 0 LIMIT 1`, listId)
+
+	var budget models.Budget
+	if err := s.scan(row, &budget); err != nil {
+		if err == sql.ErrNoRows {
+			return models.Budget{}, repositories.ErrNotFound{}
+		}
+
+		return models.Budget{}, err
+	}
+
+	return budget, nil
+}
+
+// Вернуть неудаленные бюджеты пользователя
+func (s *BudgetsReadRepository) GetActiveForOwner(ownerId string) ([]models.Budget, error) {
+	rows, err := s.db.Query(s.getSelectPartSql()+` WHERE owner_id = ? AND is_deleted = 0 ORDER BY list_id`, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := make([]models.Budget, 0)
+	for rows.Next() {
+		var budget models.Budget
+		if err := s.scan(rows, &budget); err != nil {
+			return nil, err
+		}
+
+		budgets = append(budgets, budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+func (s *BudgetsReadRepository) getSelectPartSql() string {
+	return `SELECT owner_id,
+				list_id,
+				amount,
+				currency,
+				IFNULL(UNIX_TIMESTAMP(notified_at), 0),
+				UNIX_TIMESTAMP(created_at),
+				UNIX_TIMESTAMP(updated_at),
+				is_deleted
+			FROM ` + models.BudgetTableName
+}
+
+func (s *BudgetsReadRepository) scan(row interface{ Scan(...interface{}) error }, b *models.Budget) error {
+	return row.Scan(&b.OwnerID, &b.ListID, &b.Amount, &b.Currency, &b.NotifiedAt, &b.CreatedAt, &b.UpdatedAt, &b.IsDeleted)
+}
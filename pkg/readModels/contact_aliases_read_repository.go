@@ -0,0 +1,76 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"strings"
+)
+
+type ContactAliasesReadRepository struct {
+	db *sql.DB
+}
+
+func NewContactAliasesReadRepository(db *sql.DB) ContactAliasesReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return ContactAliasesReadRepository{db: db}
+}
+
+func (s *ContactAliasesReadRepository) GetForUser(userId string) ([]models.ContactAlias, error) {
+	rows, err := s.db.Query(
+		`SELECT contact_user_id, alias FROM `+models.ContactAliasTableName+` WHERE user_id = ? ORDER BY alias`,
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := make([]models.ContactAlias, 0)
+
+	for rows.Next() {
+		var m models.ContactAlias
+		if err := rows.Scan(&m.UserId, &m.Alias); err != nil {
+			return nil, err
+		}
+
+		aliases = append(aliases, m)
+	}
+
+	return aliases, rows.Err()
+}
+
+// Вернуть, как получатели называют пользователя. Получатели без названия в результат не попадают
+func (s *ContactAliasesReadRepository) GetAliasesOf(contactUserId string, userIds []string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	if contactUserId == "" || len(userIds) == 0 {
+		return aliases, nil
+	}
+
+	args := []interface{}{contactUserId}
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT user_id, alias 
+			FROM `+models.ContactAliasTableName+` 
+			WHERE contact_user_id = ? AND user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId, alias string
+		if err := rows.Scan(&userId, &alias); err != nil {
+			return nil, err
+		}
+
+		aliases[userId] = alias
+	}
+
+	return aliases, rows.Err()
+}
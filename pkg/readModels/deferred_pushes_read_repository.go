@@ -0,0 +1,48 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type DeferredPushesReadRepository struct {
+	db *sql.DB
+}
+
+func NewDeferredPushesReadRepository(db *sql.DB) DeferredPushesReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return DeferredPushesReadRepository{db: db}
+}
+
+// Вернуть push, у которых закончились тихие часы получателя
+func (s *DeferredPushesReadRepository) GetDue(now int64, limit int) ([]models.DeferredPush, error) {
+	rows, err := s.db.Query(
+		`SELECT id, target_user_id, type, message, user_id, user_phone, list_id, item_id, UNIX_TIMESTAMP(release_at) 
+			FROM `+models.DeferredPushTableName+` 
+			WHERE release_at <= FROM_UNIXTIME(?)
+			ORDER BY id
+			LIMIT ?`,
+		now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.DeferredPush, 0)
+
+	for rows.Next() {
+		var m models.DeferredPush
+		err := rows.Scan(&m.ID, &m.TargetUserId, &m.TypeNotification, &m.Message, &m.UserId, &m.UserPhone,
+			&m.ListId, &m.ItemId, &m.ReleaseAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, m)
+	}
+
+	return result, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"shopingList/pkg/models"
 	"shopingList/pkg/repositories"
+	"strings"
 )
 
 type ItemsReadRepository struct {
@@ -23,17 +24,7 @@ func (s *ItemsReadRepository) GetUpdatedItemsForUser(listOwnerID string, receive
 	var items []models.ListItem
 	db := s.db
 	rows, err := db.Query(
-		`SELECT i.id, 
-       			i.name, 
-       			value,
-       			is_marked, 
-       			user_marked_id,
-       			list_id, 
-       			UNIX_TIMESTAMP(i.created_at), 
-       			UNIX_TIMESTAMP(i.updated_at), 
-       			UNIX_TIMESTAMP(i.received_at),
-       			 i.is_deleted 
-			FROM sl_item AS i
+		s.getSelectPartSql()+`
 			LEFT JOIN sl_item_list AS l ON (i.list_id = l.id) 
 			WHERE l.owner_id =? AND i.received_at >= FROM_UNIXTIME(?)`,
 		listOwnerID, receivedAt,
@@ -63,17 +54,7 @@ func (s *ItemsReadRepository) GetUpdatedItemsForSharedListToUser(toUserId string
 	db := s.db
 
 	rows, err := db.Query(
-		`SELECT i.id, 
-       			i.name, 
-       			value,
-       			is_marked, 
-       			user_marked_id,
-       			i.list_id, 
-       			UNIX_TIMESTAMP(i.created_at), 
-       			UNIX_TIMESTAMP(i.updated_at), 
-       			UNIX_TIMESTAMP(i.received_at),
-       			i.is_deleted
-			FROM sl_item AS i 
+		s.getSelectPartSql()+`
 			LEFT JOIN sl_shared_lists AS s  
 			ON (i.list_id = s.list_id AND status = ? AND s.is_deleted = false) 
 			WHERE s.to_user_id =? AND (i.received_at >= FROM_UNIXTIME(?) OR s.received_at >= FROM_UNIXTIME(?))`,
@@ -101,18 +82,7 @@ func (s *ItemsReadRepository) GetItemsForList(listID string) (*[]models.ListItem
 	var items []models.ListItem
 	db := s.db
 	rows, err := db.Query(
-		`SELECT id, 
-       			name, 
-       			value,
-       			is_marked, 
-       			user_marked_id,
-       			list_id, 
-       			UNIX_TIMESTAMP(created_at), 
-       			UNIX_TIMESTAMP(updated_at), 
-       			UNIX_TIMESTAMP(received_at),
-       			is_deleted 
-			FROM sl_item 
-			WHERE list_id =?`,
+		s.getSelectPartSql()+` WHERE i.list_id =?`,
 		listID,
 	)
 	if err != nil {
@@ -132,6 +102,84 @@ func (s *ItemsReadRepository) GetItemsForList(listID string) (*[]models.ListItem
 	return &items, nil
 }
 
+// Вернуть товары для нескольких списков
+func (s *ItemsReadRepository) GetItemsForLists(listIds []string) ([]models.ListItem, error) {
+	if len(listIds) == 0 {
+		return nil, nil
+	}
+
+	var args []interface{}
+	for _, id := range listIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		s.getSelectPartSql()+` WHERE i.list_id IN (?`+strings.Repeat(`,?`, len(args)-1)+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanItemRows(rows)
+}
+
+// Вернуть самые частые названия товаров из своих и акцептованных пошаренных списков пользователя
+func (s *ItemsReadRepository) GetItemNamesForUser(userId string, limit int) ([]models.ItemNameStat, error) {
+	rows, err := s.db.Query(
+		`SELECT i.name,
+				MAX(i.product_id),
+				MAX(i.category_id),
+				COUNT(*) AS cnt
+			FROM sl_item AS i
+			JOIN sl_item_list AS l ON (i.list_id = l.id)
+			LEFT JOIN sl_shared_lists AS s
+				ON (l.id = s.list_id AND s.to_user_id = ? AND s.status = ? AND s.is_deleted = 0)
+			WHERE l.owner_id = ? OR s.id IS NOT NULL
+			GROUP BY i.name
+			ORDER BY cnt DESC
+			LIMIT ?`,
+		userId, models.ShareStatusAccepted, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []models.ItemNameStat
+	for rows.Next() {
+		var n models.ItemNameStat
+		if err := rows.Scan(&n.Name, &n.ProductID, &n.CategoryID, &n.Count); err != nil {
+			return nil, err
+		}
+
+		names = append(names, n)
+	}
+
+	return names, rows.Err()
+}
+
+func (s *ItemsReadRepository) getSelectPartSql() string {
+	return `SELECT i.id,
+				i.name,
+				i.value,
+				i.quantity,
+				i.unit,
+				i.price,
+				i.currency,
+				i.product_id,
+				i.category_id,
+				i.match_confidence,
+				i.is_marked,
+				i.user_marked_id,
+				i.list_id,
+				UNIX_TIMESTAMP(i.created_at),
+				UNIX_TIMESTAMP(i.updated_at),
+				UNIX_TIMESTAMP(i.received_at),
+				i.is_deleted
+			FROM sl_item AS i `
+}
+
 func (s *ItemsReadRepository) scanItemRows(rows *sql.Rows) ([]models.ListItem, error) {
 	var items []models.ListItem
 
@@ -141,6 +189,13 @@ func (s *ItemsReadRepository) scanItemRows(rows *sql.Rows) ([]models.ListItem, e
 			&i.ID,
 			&i.Name,
 			&i.Value,
+			&i.Quantity,
+			&i.Unit,
+			&i.Price,
+			&i.Currency,
+			&i.ProductID,
+			&i.CategoryID,
+			&i.MatchConfidence,
 			&i.IsMarked,
 			&i.UserMarked,
 			&i.ListID,
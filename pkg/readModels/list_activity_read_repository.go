@@ -0,0 +1,53 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type ListActivityReadRepository struct {
+	db *sql.DB
+}
+
+func NewListActivityReadRepository(db *sql.DB) ListActivityReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return ListActivityReadRepository{db: db}
+}
+
+// Вернуть добавленные и купленные товары за период по спискам, доступным пользователю.
+// Списки без активности в результат не попадают
+func (s *ListActivityReadRepository) GetForUser(userId string, since int64, until int64) ([]models.ListActivity, error) {
+	rows, err := s.db.Query(
+		`SELECT l.id, l.name, 
+				(SELECT COUNT(*) FROM sl_item AS i 
+					WHERE i.list_id = l.id AND i.created_at >= FROM_UNIXTIME(?) AND i.created_at < FROM_UNIXTIME(?)) AS added,
+				(SELECT COUNT(*) FROM `+models.PurchaseTableName+` AS p 
+					WHERE p.list_id = l.id AND p.purchased_at >= FROM_UNIXTIME(?) AND p.purchased_at < FROM_UNIXTIME(?)) AS purchased
+			FROM sl_item_list AS l
+			LEFT JOIN sl_shared_lists AS s 
+				ON (l.id = s.list_id AND s.to_user_id = ? AND s.status = ? AND s.is_deleted = 0)
+			WHERE l.is_deleted = 0 AND (l.owner_id = ? OR s.id IS NOT NULL)
+			HAVING added > 0 OR purchased > 0
+			ORDER BY added + purchased DESC, l.name`,
+		since, until, since, until, userId, models.ShareStatusAccepted, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make([]models.ListActivity, 0)
+
+	for rows.Next() {
+		var m models.ListActivity
+		if err := rows.Scan(&m.ListId, &m.Name, &m.Added, &m.Purchased); err != nil {
+			return nil, err
+		}
+
+		activity = append(activity, m)
+	}
+
+	return activity, rows.Err()
+}
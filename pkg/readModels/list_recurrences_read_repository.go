@@ -0,0 +1,81 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+type ListRecurrencesReadRepository struct {
+	db *sql.DB
+}
+
+func NewListRecurrencesReadRepository(db *sql.DB) ListRecurrencesReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return ListRecurrencesReadRepository{db: db}
+}
+
+// Вернуть правило повторения для списка
+func (s *ListRecurrencesReadRepository) GetForList(listId string) (models.ListRecurrence, error) {
+	row := s.db.QueryRow(s.getSelectPartSql()+` WHERE list_id=?`, listId)
+
+	var recurrence models.ListRecurrence
+	err := s.scan(row, &recurrence)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.ListRecurrence{}, repositories.ErrNotFound{}
+		}
+
+		return models.ListRecurrence{}, err
+	}
+
+	return recurrence, nil
+}
+
+// Вернуть неудаленные правила, время запуска которых наступило
+func (s *ListRecurrencesReadRepository) GetDue(now int64) ([]models.ListRecurrence, error) {
+	rows, err := s.db.Query(
+		s.getSelectPartSql()+` WHERE is_deleted = 0 AND next_run_at <= FROM_UNIXTIME(?) ORDER BY next_run_at`,
+		now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ListRecurrence
+
+	for rows.Next() {
+		var recurrence models.ListRecurrence
+		if err := s.scan(rows, &recurrence); err != nil {
+			return nil, err
+		}
+
+		items = append(items, recurrence)
+	}
+
+	return items, nil
+}
+
+func (s *ListRecurrencesReadRepository) getSelectPartSql() string {
+	return `SELECT list_id,
+				owner_id,
+				type,
+				interval_days,
+				week_day,
+				month_day,
+				hour,
+				UNIX_TIMESTAMP(next_run_at),
+				IFNULL(UNIX_TIMESTAMP(last_run_at), 0),
+				UNIX_TIMESTAMP(created_at),
+				UNIX_TIMESTAMP(updated_at),
+				is_deleted
+			FROM ` + models.ListRecurrenceTableName
+}
+
+func (s *ListRecurrencesReadRepository) scan(row interface{ Scan(...interface{}) error }, r *models.ListRecurrence) error {
+	return row.Scan(&r.ListID, &r.OwnerID, &r.Type, &r.IntervalDays, &r.WeekDay, &r.MonthDay, &r.Hour,
+		&r.NextRunAt, &r.LastRunAt, &r.CreatedAt, &r.UpdatedAt, &r.IsDeleted)
+}
@@ -172,6 +172,31 @@ func (s *ListsReadRepository) GetListsSharedForUserForIds(listIds []string, user
 	return s.listRowsToArray(rows)
 }
 
+// Вернуть список, если пользователь его владелец или акцептованный участник неудаленного шаринга
+func (s *ListsReadRepository) GetListAccessibleForUser(listId string, userId string) (models.List, error) {
+	rows, err := s.DB.Query(
+		s.getSelectPartSql()+`LEFT JOIN sl_shared_lists AS s 
+				ON (l.id = s.list_id AND s.to_user_id = ? AND s.status = ? AND s.is_deleted = 0)
+			WHERE l.id = ? AND (l.owner_id = ? OR s.id IS NOT NULL)
+			LIMIT 1`,
+		userId, models.ShareStatusAccepted, listId, userId)
+	if err != nil {
+		return models.List{}, err
+	}
+	defer rows.Close()
+
+	lists, err := s.listRowsToArray(rows)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	if len(lists) == 0 {
+		return models.List{}, repositories.ErrNotFound{}
+	}
+
+	return lists[0], nil
+}
+
 func (s *ListsReadRepository) GetActiveListsForUser(userId string) ([]models.List, error) {
 	var lists []models.List
 
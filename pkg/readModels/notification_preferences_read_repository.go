@@ -0,0 +1,276 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"strings"
+	"time"
+)
+
+type NotificationPreferencesReadRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationPreferencesReadRepository(db *sql.DB) NotificationPreferencesReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return NotificationPreferencesReadRepository{db: db}
+}
+
+// Вернуть настройки пользователя. Для типов без настройки возвращается канал по умолчанию
+func (s *NotificationPreferencesReadRepository) GetForUser(userId string) ([]models.NotificationPreference, error) {
+	rows, err := s.db.Query(
+		`SELECT type, channel, email FROM `+models.NotificationPreferenceTableName+` WHERE user_id = ?`,
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := make(map[models.NotificationType]models.NotificationPreference)
+
+	for rows.Next() {
+		var p models.NotificationPreference
+		if err := rows.Scan(&p.Type, &p.Channel, &p.Email); err != nil {
+			return nil, err
+		}
+
+		saved[p.Type] = p
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, t := range models.NotificationTypes {
+		preference, ok := saved[t]
+		if !ok {
+			preference = models.NotificationPreference{Type: t, Channel: models.DefaultNotificationChannel}
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+func (s *NotificationPreferencesReadRepository) GetListMutesForUser(userId string) ([]models.NotificationListMute, error) {
+	rows, err := s.db.Query(
+		`SELECT list_id, UNIX_TIMESTAMP(muted_until) 
+			FROM `+models.NotificationListMuteTableName+` 
+			WHERE user_id = ? AND (muted_until IS NULL OR muted_until > NOW())`,
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutes := make([]models.NotificationListMute, 0)
+
+	for rows.Next() {
+		var m models.NotificationListMute
+		if err := rows.Scan(&m.ListID, &m.MutedUntil); err != nil {
+			return nil, err
+		}
+
+		mutes = append(mutes, m)
+	}
+
+	return mutes, rows.Err()
+}
+
+// Вернуть каналы получателей для типа уведомления. Получатели без настройки в результат не попадают
+func (s *NotificationPreferencesReadRepository) GetChannels(typeNotification models.NotificationType, userIds []string) (map[string]string, error) {
+	channels := make(map[string]string)
+	if len(userIds) == 0 {
+		return channels, nil
+	}
+
+	args := []interface{}{typeNotification}
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT user_id, channel 
+			FROM `+models.NotificationPreferenceTableName+` 
+			WHERE type = ? AND user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId, channel string
+		if err := rows.Scan(&userId, &channel); err != nil {
+			return nil, err
+		}
+
+		channels[userId] = channel
+	}
+
+	return channels, rows.Err()
+}
+
+// Вернуть получателей, которые отключили push по списку
+func (s *NotificationPreferencesReadRepository) GetMutedUserIds(listId string, userIds []string) (map[string]bool, error) {
+	muted := make(map[string]bool)
+	if listId == "" || len(userIds) == 0 {
+		return muted, nil
+	}
+
+	args := []interface{}{listId}
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT user_id 
+			FROM `+models.NotificationListMuteTableName+` 
+			WHERE list_id = ? AND (muted_until IS NULL OR muted_until > NOW()) 
+				AND user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+
+		muted[userId] = true
+	}
+
+	return muted, rows.Err()
+}
+
+// Часовой пояс пользователя из настроек. Если настроек нет, UTC
+func (s *NotificationPreferencesReadRepository) GetLocationForUser(userId string) (*time.Location, error) {
+	settings, err := s.GetSettingsForUser(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.Location(), nil
+}
+
+// Вернуть часовой пояс, язык и тихие часы пользователя. Если настроек нет, возвращаются значения по умолчанию
+func (s *NotificationPreferencesReadRepository) GetSettingsForUser(userId string) (models.NotificationSettings, error) {
+	settings, err := s.GetSettingsForUsers([]string{userId})
+	if err != nil {
+		return models.NotificationSettings{}, err
+	}
+
+	if result, ok := settings[userId]; ok {
+		return result, nil
+	}
+
+	return models.NewNotificationSettings(), nil
+}
+
+// Вернуть настройки получателей. Получатели без настроек в результат не попадают
+func (s *NotificationPreferencesReadRepository) GetSettingsForUsers(userIds []string) (map[string]models.NotificationSettings, error) {
+	settings := make(map[string]models.NotificationSettings)
+	if len(userIds) == 0 {
+		return settings, nil
+	}
+
+	args := make([]interface{}, 0, len(userIds))
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT user_id, timezone, locale, quiet_start, quiet_end, quiet_mode, weekly_email 
+			FROM `+models.NotificationSettingsTableName+` 
+			WHERE user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId string
+		var m models.NotificationSettings
+		if err := rows.Scan(&userId, &m.Timezone, &m.Locale, &m.QuietStart, &m.QuietEnd, &m.QuietMode,
+			&m.WeeklyEmail); err != nil {
+			return nil, err
+		}
+
+		settings[userId] = m
+	}
+
+	return settings, rows.Err()
+}
+
+// Вернуть получателей, которые подписались на email для типа уведомления и указали адрес
+func (s *NotificationPreferencesReadRepository) GetEmailRecipients(typeNotification models.NotificationType, userIds []string) ([]models.EmailRecipient, error) {
+	recipients := make([]models.EmailRecipient, 0)
+	if len(userIds) == 0 {
+		return recipients, nil
+	}
+
+	args := []interface{}{typeNotification}
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT u.id, u.email, u.name, COALESCE(ns.locale, '') 
+			FROM `+models.NotificationPreferenceTableName+` AS p
+			INNER JOIN sl_users AS u ON (u.id = p.user_id)
+			LEFT JOIN `+models.NotificationSettingsTableName+` AS ns ON (ns.user_id = p.user_id)
+			WHERE p.type = ? AND p.email = 1 AND u.is_deleted = 0 AND u.email IS NOT NULL AND u.email != ''
+				AND p.user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEmailRecipients(rows)
+}
+
+// Вернуть подписанных на еженедельную сводку, которым она не отправлялась после sentBefore.
+// Получатели идут по id, afterUserId - последний id предыдущей пачки
+func (s *NotificationPreferencesReadRepository) GetWeeklyEmailRecipients(sentBefore int64, afterUserId string, limit int) ([]models.EmailRecipient, error) {
+	rows, err := s.db.Query(
+		`SELECT u.id, u.email, u.name, ns.locale 
+			FROM `+models.NotificationSettingsTableName+` AS ns
+			INNER JOIN sl_users AS u ON (u.id = ns.user_id)
+			WHERE ns.weekly_email = 1 AND u.is_deleted = 0 AND u.email IS NOT NULL AND u.email != ''
+				AND (ns.weekly_email_sent_at IS NULL OR ns.weekly_email_sent_at < FROM_UNIXTIME(?))
+				AND u.id > ?
+			ORDER BY u.id
+			LIMIT ?`,
+		sentBefore, afterUserId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEmailRecipients(rows)
+}
+
+func scanEmailRecipients(rows *sql.Rows) ([]models.EmailRecipient, error) {
+	recipients := make([]models.EmailRecipient, 0)
+
+	for rows.Next() {
+		var m models.EmailRecipient
+		if err := rows.Scan(&m.UserId, &m.Email, &m.Name, &m.Locale); err != nil {
+			return nil, err
+		}
+
+		recipients = append(recipients, m)
+	}
+
+	return recipients, rows.Err()
+}
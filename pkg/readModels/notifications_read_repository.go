@@ -2,9 +2,8 @@ package readModels
 
 import (
 	"database/sql"
-	"fmt"
 	"shopingList/pkg/models"
-	"shopingList/pkg/repositories"
+	"strings"
 )
 
 type NotificationsReadRepository struct {
@@ -19,65 +18,94 @@ func NewNotificationsReadRepository(db *sql.DB) NotificationsReadRepository {
 	return NotificationsReadRepository{db: db}
 }
 
-func (s *NotificationsReadRepository) GetTotalForUser(userId string) (int, error) {
-	rows, err := s.db.Query(
-		`SELECT count(*) 
-			FROM `+models.NotificationTableName+`
-			WHERE target_user_id =?`,
-		userId,
-	)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, repositories.ErrNotFound{}
+// Вернуть уведомления пользователя от новых к старым, начиная после cursor.
+// Без cursor возвращается начало ленты
+func (s *NotificationsReadRepository) GetPageForUser(
+	userId string,
+	filter models.NotificationFilter,
+	cursor *models.NotificationCursor,
+	limit int) ([]models.Notification, error) {
+	where := []string{"target_user_id = ?", "is_deleted = 0"}
+	args := []interface{}{userId}
+
+	if len(filter.Types) > 0 {
+		where = append(where, "type IN (?"+strings.Repeat(",?", len(filter.Types)-1)+")")
+		for _, t := range filter.Types {
+			args = append(args, t)
 		}
+	}
 
-		return 0, err
+	if filter.ListId != "" {
+		where = append(where, "list_id = ?")
+		args = append(args, filter.ListId)
+	}
+
+	if cursor != nil {
+		where = append(where, "(created_at < FROM_UNIXTIME(?) OR (created_at = FROM_UNIXTIME(?) AND id < ?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
 	}
-	defer rows.Close()
 
-	count := 0
+	args = append(args, limit)
 
-	for rows.Next() {
-		if err := rows.Scan(&count); err != nil {
-			return 0, err
-		}
+	rows, err := s.db.Query(
+		`SELECT id, type, message, template, params, user_id, user_phone, list_id, item_id, UNIX_TIMESTAMP(read_at), UNIX_TIMESTAMP(created_at) 
+			FROM `+models.NotificationTableName+`
+			WHERE `+strings.Join(where, " AND ")+`
+			ORDER BY created_at DESC, id DESC
+			LIMIT ?`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return count, nil
+	return s.scanRows(rows)
 }
 
-func (s *NotificationsReadRepository) GetBatchForUser(userId string, page int, limit int) ([]models.Notification, error) {
-	var items []models.Notification
+// Количество непрочитанных уведомлений пользователя
+func (s *NotificationsReadRepository) GetUnreadCountForUser(userId string) (int, error) {
+	var count int
 
-	if page <= 0 {
-		page = 1
-	}
+	err := s.db.QueryRow(
+		`SELECT count(*) 
+			FROM `+models.NotificationTableName+`
+			WHERE target_user_id = ? AND is_deleted = 0 AND read_at IS NULL`,
+		userId,
+	).Scan(&count)
 
-	start := page*limit - limit
+	if err != nil {
+		return 0, err
+	}
 
-	sqlLimit := fmt.Sprintf("LIMIT %d, %d", start, limit)
+	return count, nil
+}
 
+// Вернуть уведомления, которые прочитали или удалили начиная с даты updatedAt
+func (s *NotificationsReadRepository) GetUpdatedStatesForUser(userId string, updatedAt int64) ([]models.NotificationState, error) {
 	rows, err := s.db.Query(
-		`SELECT id, type, message, user_id, user_phone, list_id, item_id, UNIX_TIMESTAMP(created_at) 
+		`SELECT id, UNIX_TIMESTAMP(read_at), is_deleted, UNIX_TIMESTAMP(updated_at) 
 			FROM `+models.NotificationTableName+`
-			WHERE target_user_id =? ORDER BY created_at DESC `+sqlLimit,
-		userId,
+			WHERE target_user_id = ? AND updated_at >= FROM_UNIXTIME(?)`,
+		userId, updatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, repositories.ErrNotFound{}
-		}
-
 		return nil, err
 	}
 	defer rows.Close()
 
-	items, err = s.scanRows(rows)
-	if err != nil {
-		return nil, err
+	var states []models.NotificationState
+
+	for rows.Next() {
+		var state models.NotificationState
+		if err := rows.Scan(&state.ID, &state.ReadAt, &state.IsDeleted, &state.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
 	}
 
-	return items, nil
+	return states, rows.Err()
 }
 
 func (s *NotificationsReadRepository) scanRows(rows *sql.Rows) ([]models.Notification, error) {
@@ -85,11 +113,12 @@ func (s *NotificationsReadRepository) scanRows(rows *sql.Rows) ([]models.Notific
 
 	for rows.Next() {
 		var model models.Notification
-		err := rows.Scan(&model.ID, &model.TypeNotification, &model.Message, &model.UserId, &model.UserPhone, &model.ListId, &model.ItemId, &model.CreatedAt)
+		err := rows.Scan(&model.ID, &model.TypeNotification, &model.Message, &model.Template, &model.Params, &model.UserId, &model.UserPhone, &model.ListId, &model.ItemId, &model.ReadAt, &model.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
+		model.IsRead = model.ReadAt.Valid
 		items = append(items, model)
 	}
-	return items, nil
+	return items, rows.Err()
 }
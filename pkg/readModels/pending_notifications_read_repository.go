@@ -0,0 +1,78 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type PendingNotificationsReadRepository struct {
+	db *sql.DB
+}
+
+func NewPendingNotificationsReadRepository(db *sql.DB) PendingNotificationsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return PendingNotificationsReadRepository{db: db}
+}
+
+const pendingNotificationFields = `p.id, p.target_user_id, p.user_id, p.user_name, p.user_phone, p.list_id, p.list_name, 
+		p.item_id, p.item_name, p.type, p.push, p.is_digest, UNIX_TIMESTAMP(p.created_at)`
+
+// Вернуть события групп (получатель, автор, список), которые пора отправить:
+// после последнего события группы прошло окно тишины или первое событие ждет дольше допустимого
+func (s *PendingNotificationsReadRepository) GetDueGroups(quietSince int64, startedBefore int64) ([]models.PendingNotification, error) {
+	rows, err := s.db.Query(
+		`SELECT `+pendingNotificationFields+` 
+			FROM `+models.PendingNotificationTableName+` p
+			JOIN (
+				SELECT target_user_id, user_id, list_id 
+				FROM `+models.PendingNotificationTableName+` 
+				WHERE is_digest = 0
+				GROUP BY target_user_id, user_id, list_id
+				HAVING MAX(created_at) <= FROM_UNIXTIME(?) OR MIN(created_at) <= FROM_UNIXTIME(?)
+			) g ON g.target_user_id = p.target_user_id AND g.user_id = p.user_id AND g.list_id = p.list_id
+			WHERE p.is_digest = 0
+			ORDER BY p.id`,
+		quietSince, startedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPendingNotifications(rows)
+}
+
+// Вернуть события для дайджеста, накопленные до указанного времени
+func (s *PendingNotificationsReadRepository) GetDigestBefore(until int64) ([]models.PendingNotification, error) {
+	rows, err := s.db.Query(
+		`SELECT `+pendingNotificationFields+` 
+			FROM `+models.PendingNotificationTableName+` p
+			WHERE p.is_digest = 1 AND p.created_at < FROM_UNIXTIME(?)
+			ORDER BY p.id`,
+		until)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPendingNotifications(rows)
+}
+
+func scanPendingNotifications(rows *sql.Rows) ([]models.PendingNotification, error) {
+	defer rows.Close()
+
+	result := make([]models.PendingNotification, 0)
+
+	for rows.Next() {
+		var m models.PendingNotification
+		err := rows.Scan(&m.ID, &m.TargetUserId, &m.UserId, &m.UserName, &m.UserPhone, &m.ListId, &m.ListName,
+			&m.ItemId, &m.ItemName, &m.TypeNotification, &m.Push, &m.IsDigest, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, m)
+	}
+
+	return result, rows.Err()
+}
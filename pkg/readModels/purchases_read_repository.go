@@ -0,0 +1,109 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type PurchasesReadRepository struct {
+	db *sql.DB
+}
+
+func NewPurchasesReadRepository(db *sql.DB) PurchasesReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return PurchasesReadRepository{db: db}
+}
+
+// Вернуть покупки за период по спискам, доступным пользователю. Если listId не пустой, то только по этому списку
+func (s *PurchasesReadRepository) GetForUser(userId string, listId string, from int64, to int64) ([]models.Purchase, error) {
+	query := s.getSelectPartSql() + `
+			JOIN sl_item_list AS l ON (p.list_id = l.id)
+			LEFT JOIN sl_shared_lists AS s
+				ON (l.id = s.list_id AND s.to_user_id = ? AND s.status = ? AND s.is_deleted = 0)
+			WHERE (l.owner_id = ? OR s.id IS NOT NULL)
+				AND p.purchased_at >= FROM_UNIXTIME(?) AND p.purchased_at < FROM_UNIXTIME(?)`
+	args := []interface{}{userId, models.ShareStatusAccepted, userId, from, to}
+
+	if listId != "" {
+		query += ` AND p.list_id = ?`
+		args = append(args, listId)
+	}
+
+	rows, err := s.db.Query(query+` ORDER BY p.purchased_at DESC, p.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanRows(rows)
+}
+
+func (s *PurchasesReadRepository) getSelectPartSql() string {
+	return `SELECT p.id,
+				p.trip_id,
+				p.list_id,
+				p.item_id,
+				p.user_id,
+				p.store_id,
+				p.name,
+				p.quantity,
+				p.unit,
+				p.price,
+				p.currency,
+				p.product_id,
+				p.category_id,
+				UNIX_TIMESTAMP(p.item_updated_at),
+				UNIX_TIMESTAMP(p.purchased_at)
+			FROM ` + models.PurchaseTableName + ` AS p `
+}
+
+func (s *PurchasesReadRepository) scanRows(rows *sql.Rows) ([]models.Purchase, error) {
+	purchases := make([]models.Purchase, 0)
+
+	for rows.Next() {
+		var p models.Purchase
+		err := rows.Scan(&p.ID, &p.TripID, &p.ListID, &p.ItemID, &p.UserID, &p.StoreID, &p.Name, &p.Quantity,
+			&p.Unit, &p.Price, &p.Currency, &p.ProductID, &p.CategoryID, &p.ItemUpdatedAt, &p.PurchasedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		purchases = append(purchases, p)
+	}
+
+	return purchases, rows.Err()
+}
+
+// Вернуть пользователей, которым доступны списки с покупками начиная с from: купивших, владельцев и участников списков
+func (s *PurchasesReadRepository) GetUserIdsWithPurchasesSince(from int64) ([]string, error) {
+	rows, err := s.db.Query(
+		`SELECT p.user_id FROM `+models.PurchaseTableName+` AS p WHERE p.purchased_at >= FROM_UNIXTIME(?)
+		UNION
+		SELECT l.owner_id FROM `+models.PurchaseTableName+` AS p 
+			JOIN sl_item_list AS l ON (p.list_id = l.id) 
+			WHERE p.purchased_at >= FROM_UNIXTIME(?)
+		UNION
+		SELECT s.to_user_id FROM `+models.PurchaseTableName+` AS p 
+			JOIN sl_shared_lists AS s ON (p.list_id = s.list_id AND s.status = ? AND s.is_deleted = 0) 
+			WHERE p.purchased_at >= FROM_UNIXTIME(?)`,
+		from, from, models.ShareStatusAccepted, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,63 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type PushJobsReadRepository struct {
+	db *sql.DB
+}
+
+func NewPushJobsReadRepository(db *sql.DB) PushJobsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return PushJobsReadRepository{db: db}
+}
+
+func (s *PushJobsReadRepository) GetStats() (models.PushQueueStats, error) {
+	var stats models.PushQueueStats
+
+	err := s.db.QueryRow(
+		`SELECT COALESCE(SUM(attempts = 0), 0), COALESCE(SUM(attempts > 0), 0) FROM `+models.PushJobTableName,
+	).Scan(&stats.Pending, &stats.Retrying)
+	if err != nil {
+		return stats, err
+	}
+
+	err = s.db.QueryRow(`SELECT COUNT(*) FROM ` + models.PushDeadLetterTableName).Scan(&stats.Dead)
+
+	return stats, err
+}
+
+// Последние задания из dead letter
+func (s *PushJobsReadRepository) GetDeadLetters(limit int) ([]models.PushJob, error) {
+	rows, err := s.db.Query(
+		`SELECT id, target_user_id, type, message, user_id, user_phone, list_id, item_id, silent, tokens, attempts, last_error, 
+				UNIX_TIMESTAMP(created_at), UNIX_TIMESTAMP(failed_at) 
+			FROM `+models.PushDeadLetterTableName+` 
+			ORDER BY failed_at DESC, id DESC
+			LIMIT ?`,
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.PushJob, 0)
+
+	for rows.Next() {
+		var m models.PushJob
+		err := rows.Scan(&m.ID, &m.TargetUserId, &m.TypeNotification, &m.Message, &m.UserId, &m.UserPhone, &m.ListId,
+			&m.ItemId, &m.Silent, &m.Tokens, &m.Attempts, &m.LastError, &m.CreatedAt, &m.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, m)
+	}
+
+	return jobs, rows.Err()
+}
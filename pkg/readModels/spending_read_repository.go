@@ -0,0 +1,131 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type SpendingReadRepository struct {
+	db *sql.DB
+}
+
+func NewSpendingReadRepository(db *sql.DB) SpendingReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return SpendingReadRepository{db: db}
+}
+
+// Вернуть плановую и фактическую сумму списка в разрезе валют
+func (s *SpendingReadRepository) GetListTotals(listId string) ([]models.ListTotal, error) {
+	rows, err := s.db.Query(
+		`SELECT currency,
+				SUM(price),
+				SUM(IF(is_marked, price, 0)),
+				COUNT(*),
+				SUM(IF(is_marked, 1, 0))
+			FROM sl_item
+			WHERE list_id = ? AND is_deleted = 0 AND price IS NOT NULL
+			GROUP BY currency
+			ORDER BY currency`,
+		listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make([]models.ListTotal, 0)
+	for rows.Next() {
+		var t models.ListTotal
+		if err := rows.Scan(&t.Currency, &t.Planned, &t.Actual, &t.ItemsCount, &t.MarkedCount); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
+}
+
+// Вернуть траты по списку в разрезе участников, отметивших товары
+func (s *SpendingReadRepository) GetListMemberTotals(listId string) ([]models.MemberSpend, error) {
+	rows, err := s.db.Query(
+		`SELECT user_marked_id,
+				currency,
+				SUM(price),
+				COUNT(*)
+			FROM sl_item
+			WHERE list_id = ? AND is_deleted = 0 AND is_marked = 1 AND price IS NOT NULL
+				AND user_marked_id IS NOT NULL
+			GROUP BY user_marked_id, currency
+			ORDER BY user_marked_id, currency`,
+		listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spends := make([]models.MemberSpend, 0)
+	for rows.Next() {
+		var m models.MemberSpend
+		if err := rows.Scan(&m.UserID, &m.Currency, &m.Actual, &m.ItemsCount); err != nil {
+			return nil, err
+		}
+		spends = append(spends, m)
+	}
+
+	return spends, rows.Err()
+}
+
+// Вернуть траты за период по доступным пользователю спискам в разрезе списков, участников и валют.
+// Время покупки определяется по времени последнего изменения отмеченного товара
+func (s *SpendingReadRepository) GetSpendForUser(userId string, from int64, to int64) ([]models.ListSpend, error) {
+	rows, err := s.db.Query(
+		`SELECT l.id,
+				l.name,
+				i.user_marked_id,
+				i.currency,
+				SUM(i.price),
+				COUNT(*)
+			FROM sl_item AS i
+			JOIN sl_item_list AS l ON (i.list_id = l.id)
+			LEFT JOIN sl_shared_lists AS s
+				ON (l.id = s.list_id AND s.to_user_id = ? AND s.status = ? AND s.is_deleted = 0)
+			WHERE (l.owner_id = ? OR s.id IS NOT NULL)
+				AND l.is_deleted = 0 AND l.is_template = 0
+				AND i.is_deleted = 0 AND i.is_marked = 1 AND i.price IS NOT NULL
+				AND i.user_marked_id IS NOT NULL
+				AND i.updated_at >= FROM_UNIXTIME(?) AND i.updated_at < FROM_UNIXTIME(?)
+			GROUP BY l.id, l.name, i.user_marked_id, i.currency
+			ORDER BY l.name, i.user_marked_id, i.currency`,
+		userId, models.ShareStatusAccepted, userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spends := make([]models.ListSpend, 0)
+	for rows.Next() {
+		var ls models.ListSpend
+		if err := rows.Scan(&ls.ListID, &ls.ListName, &ls.UserID, &ls.Currency, &ls.Actual, &ls.ItemsCount); err != nil {
+			return nil, err
+		}
+		spends = append(spends, ls)
+	}
+
+	return spends, rows.Err()
+}
+
+// Вернуть сумму товаров в валюте, отмеченных пользователем за период
+func (s *SpendingReadRepository) GetMarkedByUserTotal(userId string, currency string, from int64, to int64) (float64, error) {
+	var total float64
+
+	err := s.db.QueryRow(
+		`SELECT IFNULL(SUM(price), 0)
+			FROM sl_item
+			WHERE user_marked_id = ? AND currency = ? AND is_deleted = 0 AND is_marked = 1 AND price IS NOT NULL
+				AND updated_at >= FROM_UNIXTIME(?) AND updated_at < FROM_UNIXTIME(?)`,
+		userId, currency, from, to).Scan(&total)
+
+	return total, err
+}
@@ -0,0 +1,84 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+type StoresReadRepository struct {
+	db *sql.DB
+}
+
+func NewStoresReadRepository(db *sql.DB) StoresReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return StoresReadRepository{db: db}
+}
+
+// Вернуть обновленные магазины пользователя
+func (s *StoresReadRepository) GetUpdatedStoresForUser(ownerID string, receivedAt int64) ([]models.Store, error) {
+	rows, err := s.db.Query(
+		s.getSelectPartSql()+` WHERE owner_id = ? AND received_at >= FROM_UNIXTIME(?)`,
+		ownerID, receivedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanRows(rows)
+}
+
+// Вернуть неудаленный магазин пользователя
+func (s *StoresReadRepository) GetStoreForOwner(id string, ownerId string) (models.Store, error) {
+	rows, err := s.db.Query(
+		s.getSelectPartSql()+` WHERE id = ? AND owner_id = ? AND is_deleted = 0`,
+		id, ownerId,
+	)
+	if err != nil {
+		return models.Store{}, err
+	}
+	defer rows.Close()
+
+	stores, err := s.scanRows(rows)
+	if err != nil {
+		return models.Store{}, err
+	}
+
+	if len(stores) == 0 {
+		return models.Store{}, repositories.ErrNotFound{}
+	}
+
+	return stores[0], nil
+}
+
+func (s *StoresReadRepository) getSelectPartSql() string {
+	return `SELECT id,
+				owner_id,
+				name,
+				category_order,
+				UNIX_TIMESTAMP(created_at),
+				UNIX_TIMESTAMP(updated_at),
+				UNIX_TIMESTAMP(received_at),
+				is_deleted
+			FROM ` + models.StoreTableName
+}
+
+func (s *StoresReadRepository) scanRows(rows *sql.Rows) ([]models.Store, error) {
+	var stores []models.Store
+
+	for rows.Next() {
+		var i models.Store
+		err := rows.Scan(&i.ID, &i.OwnerID, &i.Name, &i.CategoryOrder, &i.CreatedAt, &i.UpdatedAt, &i.ReceivedAt, &i.IsDeleted)
+		if err != nil {
+			return nil, err
+		}
+
+		stores = append(stores, i)
+	}
+
+	return stores, rows.Err()
+}
@@ -0,0 +1,82 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+)
+
+type SuggestionsReadRepository struct {
+	db *sql.DB
+}
+
+func NewSuggestionsReadRepository(db *sql.DB) SuggestionsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return SuggestionsReadRepository{db: db}
+}
+
+// Вернуть статистику покупок пользователя
+func (s *SuggestionsReadRepository) GetStatsForUser(userId string) ([]models.ProductStat, error) {
+	rows, err := s.db.Query(
+		`SELECT user_id,
+				product_key,
+				name,
+				product_id,
+				category_id,
+				purchases_count,
+				interval_days,
+				UNIX_TIMESTAMP(last_bought_at)
+			FROM `+models.SuggestionProductsTableName+`
+			WHERE user_id = ?`,
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]models.ProductStat, 0)
+	for rows.Next() {
+		var st models.ProductStat
+		err := rows.Scan(&st.UserID, &st.ProductKey, &st.Name, &st.ProductID, &st.CategoryID,
+			&st.PurchasesCount, &st.IntervalDays, &st.LastBoughtAt)
+		if err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
+
+// Вернуть пары товаров, которые пользователь покупает вместе
+func (s *SuggestionsReadRepository) GetPairsForUser(userId string) ([]models.ProductPair, error) {
+	rows, err := s.db.Query(
+		`SELECT user_id,
+				product_key,
+				related_key,
+				related_name,
+				support,
+				confidence
+			FROM `+models.SuggestionPairsTableName+`
+			WHERE user_id = ?`,
+		userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pairs := make([]models.ProductPair, 0)
+	for rows.Next() {
+		var p models.ProductPair
+		if err := rows.Scan(&p.UserID, &p.ProductKey, &p.RelatedKey, &p.RelatedName, &p.Support, &p.Confidence); err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, p)
+	}
+
+	return pairs, rows.Err()
+}
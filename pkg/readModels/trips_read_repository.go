@@ -0,0 +1,55 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+type TripsReadRepository struct {
+	db *sql.DB
+}
+
+func NewTripsReadRepository(db *sql.DB) TripsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return TripsReadRepository{db: db}
+}
+
+func (s *TripsReadRepository) GetTrip(id string) (models.Trip, error) {
+	return s.getOne(s.getSelectPartSql()+` WHERE id = ?`, id)
+}
+
+// Вернуть активный поход по списку
+func (s *TripsReadRepository) GetActiveForList(listId string) (models.Trip, error) {
+	return s.getOne(s.getSelectPartSql()+` WHERE list_id = ? AND status = ? ORDER BY started_at DESC LIMIT 1`,
+		listId, models.TripStatusActive)
+}
+
+func (s *TripsReadRepository) getOne(query string, args ...interface{}) (models.Trip, error) {
+	var t models.Trip
+
+	err := s.db.QueryRow(query, args...).Scan(&t.ID, &t.ListID, &t.UserID, &t.StoreID, &t.Status, &t.StartedAt, &t.FinishedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Trip{}, repositories.ErrNotFound{}
+		}
+
+		return models.Trip{}, err
+	}
+
+	return t, nil
+}
+
+func (s *TripsReadRepository) getSelectPartSql() string {
+	return `SELECT id,
+				list_id,
+				user_id,
+				store_id,
+				status,
+				UNIX_TIMESTAMP(started_at),
+				IFNULL(UNIX_TIMESTAMP(finished_at), 0)
+			FROM ` + models.TripTableName
+}
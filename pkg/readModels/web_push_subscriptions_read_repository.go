@@ -0,0 +1,53 @@
+package readModels
+
+import (
+	"database/sql"
+	"shopingList/pkg/models"
+	"strings"
+)
+
+type WebPushSubscriptionsReadRepository struct {
+	db *sql.DB
+}
+
+func NewWebPushSubscriptionsReadRepository(db *sql.DB) WebPushSubscriptionsReadRepository {
+	if db == nil {
+		panic("DB is nil")
+	}
+
+	return WebPushSubscriptionsReadRepository{db: db}
+}
+
+// Вернуть подписки пользователей в виде токенов для отправки push
+func (s *WebPushSubscriptionsReadRepository) GetTokensForUsers(userIds []string) ([]models.FCMToken, error) {
+	tokens := make([]models.FCMToken, 0)
+	if len(userIds) == 0 {
+		return tokens, nil
+	}
+
+	args := make([]interface{}, 0, len(userIds))
+	for _, id := range userIds {
+		args = append(args, id)
+	}
+
+	rows, err := s.db.Query(
+		`SELECT endpoint, p256dh, auth 
+			FROM `+models.WebPushSubscriptionTableName+` 
+			WHERE user_id IN (?`+strings.Repeat(`,?`, len(userIds)-1)+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.WebPushSubscription
+		if err := rows.Scan(&m.Endpoint, &m.Keys.P256dh, &m.Keys.Auth); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, m.Token())
+	}
+
+	return tokens, rows.Err()
+}
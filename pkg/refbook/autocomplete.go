@@ -0,0 +1,278 @@
+package refbook
+
+import (
+	"math"
+	"shopingList/pkg/models"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	CompletionSourceRefbook  = "refbook"
+	CompletionSourceFavorite = "favorite"
+	CompletionSourceHistory  = "history"
+)
+
+const (
+	DefaultCompletions = 10
+	layoutPenalty      = 0.95 // Совпадение после смены раскладки чуть хуже прямого
+	synonymPenalty     = 0.9  // Совпадение с синонимом хуже совпадения с названием
+	favoriteBonus      = 0.15
+	historyBonus       = 0.1 // Максимальный бонус за частые покупки
+	historyBonusCount  = 5   // Сколько раз нужно купить товар для максимального бонуса
+)
+
+// Кандидат для подсказки при наборе названия товара
+type CompletionCandidate struct {
+	Name       string
+	ProductID  models.NullInt64
+	CategoryID models.NullInt64
+	Source     string
+	Count      int      // Сколько раз пользователь добавлял товар, для истории
+	Aliases    []string // Синонимы, по ним тоже ищется, но в подсказке показывается название
+}
+
+// Подсказка при наборе названия товара
+type Completion struct {
+	Name       string           `json:"name"`
+	ProductID  models.NullInt64 `json:"product_id"`
+	CategoryID models.NullInt64 `json:"category_id"`
+	Source     string           `json:"source"`
+	Score      float64          `json:"score"`
+}
+
+// Подобрать подсказки для введенной строки.
+// Поддерживаются префиксы слов, опечатки, е/ё и набор в неправильной раскладке
+func Complete(query string, candidates []CompletionCandidate, limit int) []Completion {
+	query = normalizeName(query)
+	if query == "" {
+		return []Completion{}
+	}
+
+	queries := map[string]float64{query: 1}
+	if switched, ok := SwitchLayout(query); ok && switched != query {
+		queries[normalizeName(switched)] = layoutPenalty
+	}
+
+	result := make(map[string]Completion)
+
+	for _, candidate := range candidates {
+		name := normalizeName(candidate.Name)
+
+		var score float64
+		for q, weight := range queries {
+			if s := matchScore(q, name) * weight; s > score {
+				score = s
+			}
+
+			for _, alias := range candidate.Aliases {
+				if s := matchScore(q, normalizeName(alias)) * weight * synonymPenalty; s > score {
+					score = s
+				}
+			}
+		}
+
+		if score == 0 {
+			continue
+		}
+
+		switch candidate.Source {
+		case CompletionSourceFavorite:
+			score += favoriteBonus
+		case CompletionSourceHistory:
+			count := candidate.Count
+			if count > historyBonusCount {
+				count = historyBonusCount
+			}
+			score += historyBonus * float64(count) / historyBonusCount
+		}
+
+		score = math.Round(score*1000) / 1000
+		key := completionKey(candidate.ProductID, name)
+
+		exist, ok := result[key]
+		if ok && exist.Score >= score {
+			if !exist.CategoryID.Valid && candidate.CategoryID.Valid {
+				exist.CategoryID = candidate.CategoryID
+				result[key] = exist
+			}
+
+			continue
+		}
+
+		completion := Completion{
+			Name:       candidate.Name,
+			ProductID:  candidate.ProductID,
+			CategoryID: candidate.CategoryID,
+			Source:     candidate.Source,
+			Score:      score,
+		}
+
+		if ok && !completion.CategoryID.Valid {
+			completion.CategoryID = exist.CategoryID
+		}
+
+		result[key] = completion
+	}
+
+	completions := make([]Completion, 0, len(result))
+	for _, completion := range result {
+		completions = append(completions, completion)
+	}
+
+	sort.Slice(completions, func(i, j int) bool {
+		if completions[i].Score != completions[j].Score {
+			return completions[i].Score > completions[j].Score
+		}
+
+		if len(completions[i].Name) != len(completions[j].Name) {
+			return len(completions[i].Name) < len(completions[j].Name)
+		}
+
+		return completions[i].Name < completions[j].Name
+	})
+
+	if limit <= 0 {
+		limit = DefaultCompletions
+	}
+
+	if len(completions) > limit {
+		completions = completions[:limit]
+	}
+
+	return completions
+}
+
+// Вернуть кандидатов из справочника
+func (m *Matcher) CompletionCandidates() []CompletionCandidate {
+	candidates := make([]CompletionCandidate, 0, len(m.products))
+
+	for _, p := range m.products {
+		if p.isSynonym {
+			continue
+		}
+
+		candidates = append(candidates, CompletionCandidate{
+			Name:       p.product.Title,
+			ProductID:  models.NewNullInt64(p.product.ID),
+			CategoryID: models.NewNullInt64(p.product.CategoryId),
+			Source:     CompletionSourceRefbook,
+			Aliases:    p.product.Synonyms,
+		})
+	}
+
+	return candidates
+}
+
+// Оценка совпадения запроса с названием:
+// префикс названия, префикс слова, подстрока и префикс слова с опечатками
+func matchScore(query string, name string) float64 {
+	if strings.HasPrefix(name, query) {
+		return 1
+	}
+
+	words := strings.Fields(name)
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return 0.9
+		}
+	}
+
+	if strings.Contains(name, query) {
+		return 0.7
+	}
+
+	maxDistance := allowedTypos(query)
+	if maxDistance == 0 {
+		return 0
+	}
+
+	queryRunes := []rune(query)
+	best := maxDistance + 1
+
+	// Сравнить с префиксами слов и всего названия такой же длины, с запасом на пропущенную или лишнюю букву
+	for _, word := range append(words, name) {
+		wordRunes := []rune(word)
+
+		for l := len(queryRunes) - 1; l <= len(queryRunes)+1; l++ {
+			if l <= 0 || l > len(wordRunes) {
+				continue
+			}
+
+			if d := distance(queryRunes, wordRunes[:l]); d < best {
+				best = d
+			}
+		}
+	}
+
+	if best > maxDistance {
+		return 0
+	}
+
+	return 0.6 - 0.1*float64(best)
+}
+
+// Допустимое количество опечаток зависит от длины запроса
+func allowedTypos(query string) int {
+	switch length := utf8.RuneCountInString(query); {
+	case length <= 3:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Расстояние Дамерау-Левенштейна: вставка, удаление, замена и перестановка соседних букв
+func distance(a []rune, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+
+	for j := 0; j <= len(b); j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(minInt(d[i-1][j]+1, d[i][j-1]+1), d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+func normalizeName(value string) string {
+	value = strings.ReplaceAll(strings.ToLower(value), "ё", "е")
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func completionKey(productId models.NullInt64, name string) string {
+	if productId.Valid {
+		return "p:" + strconv.FormatInt(productId.Int64, 10)
+	}
+
+	return "n:" + name
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
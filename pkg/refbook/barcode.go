@@ -0,0 +1,51 @@
+package refbook
+
+import "strings"
+
+// Привести штрихкод к виду, в котором он хранится, и проверить контрольную цифру.
+// Поддерживаются EAN-8, EAN-13 и UPC-A. UPC-A хранится как EAN-13 с ведущим нулем,
+// поэтому один и тот же товар находится по обоим вариантам
+func NormalizeBarcode(value string) (string, bool) {
+	value = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+
+		return r
+	}, value)
+
+	if len(value) != 8 && len(value) != 12 && len(value) != 13 {
+		return "", false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+
+	if !isValidBarcodeChecksum(value) {
+		return "", false
+	}
+
+	if len(value) == 12 {
+		value = "0" + value
+	}
+
+	return value, true
+}
+
+// Контрольная цифра GTIN: веса 3 и 1 чередуются справа налево, начиная с цифры перед контрольной
+func isValidBarcodeChecksum(value string) bool {
+	sum := 0
+	weight := 3
+
+	for i := len(value) - 2; i >= 0; i-- {
+		sum += int(value[i]-'0') * weight
+		weight = 4 - weight
+	}
+
+	check := (10 - sum%10) % 10
+
+	return check == int(value[len(value)-1]-'0')
+}
@@ -0,0 +1,70 @@
+package refbook
+
+import (
+	"shopingList/pkg/models"
+	"sort"
+	"strconv"
+)
+
+// Товары списка, которые относятся к одному товару справочника или называются одинаково
+type DuplicateGroup struct {
+	ProductID models.NullInt64  `json:"product_id"`
+	Title     string            `json:"title"`
+	Items     []models.ListItem `json:"items"`
+}
+
+// Найти дубли среди не купленных товаров списка.
+// Товар определяется по ссылке на справочник, затем по сопоставлению названия (с учетом синонимов),
+// затем по нормализованному названию
+func FindDuplicates(items []models.ListItem, matcher *Matcher) []DuplicateGroup {
+	groups := make(map[string]*DuplicateGroup)
+	var keys []string
+
+	for _, item := range items {
+		if item.IsDeleted || item.IsMarked {
+			continue
+		}
+
+		productId := item.ProductID
+		title := item.Name
+
+		if !productId.Valid && matcher != nil {
+			if match, ok := matcher.Match(item.Name); ok {
+				productId = models.NewNullInt64(match.ProductID)
+			}
+		}
+
+		if productId.Valid && matcher != nil {
+			if product, ok := matcher.Product(productId.Int64); ok {
+				title = product.Title
+			}
+		}
+
+		key := "n:" + NormalizeTitle(item.Name)
+		if productId.Valid {
+			key = "p:" + strconv.FormatInt(productId.Int64, 10)
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &DuplicateGroup{ProductID: productId, Title: title}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+
+		group.Items = append(group.Items, item)
+	}
+
+	result := make([]DuplicateGroup, 0)
+	for _, key := range keys {
+		if len(groups[key].Items) > 1 {
+			result = append(result, *groups[key])
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Title < result[j].Title
+	})
+
+	return result
+}
@@ -0,0 +1,189 @@
+package refbook
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"shopingList/pkg/i18n"
+	"strconv"
+	"strings"
+)
+
+const (
+	importColumnID       = "id"
+	importColumnCategory = "category"
+	importColumnTitle    = "title"
+	importColumnSynonyms = "synonyms"
+	importColumnBarcodes = "barcodes"
+	importColumnUnit     = "unit"
+)
+
+// Порядок колонок файла без заголовка
+var defaultImportColumns = []string{
+	importColumnCategory, importColumnTitle, importColumnSynonyms, importColumnBarcodes, importColumnUnit}
+
+// Синонимы колонок в заголовке
+var importColumnAliases = map[string]string{
+	"barcode":      importColumnBarcodes,
+	"default_unit": importColumnUnit,
+	"product":      importColumnTitle,
+}
+
+// Разделитель нескольких значений в одной колонке
+const importValuesSeparator = "|"
+
+// Прочитать справочник из csv с разделителем ";".
+// Первая строка может быть заголовком с названиями колонок, иначе колонки идут в порядке
+// category;title;synonyms;barcodes;unit, обязательны только первые две.
+// Переводы задаются только в файле с заголовком колонками title_<язык> и category_<язык>
+func ReadImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading csv")
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := defaultImportColumns
+	if header, ok := parseImportHeader(records[0]); ok {
+		columns = header
+		records = records[1:]
+	}
+
+	rows := make([]ImportRow, 0, len(records))
+
+	for i, record := range records {
+		if len(record) < 2 || len(record) > len(columns) {
+			return nil, errors.Errorf("line %d: wrong number of fields %d", i+1, len(record))
+		}
+
+		var row ImportRow
+
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+
+			switch columns[j] {
+			case importColumnID:
+				if value == "" {
+					continue
+				}
+
+				row.ID, err = strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("line %d: wrong id %s", i+1, value)
+				}
+			case importColumnCategory:
+				row.CategoryTitle = value
+			case importColumnTitle:
+				row.Title = value
+			case importColumnSynonyms:
+				row.Synonyms = splitImportValues(value)
+			case importColumnBarcodes:
+				row.Barcodes = splitImportValues(value)
+			case importColumnUnit:
+				unit := value
+				row.DefaultUnit = &unit
+			default:
+				if err := setImportTranslation(&row, columns[j], value); err != nil {
+					return nil, errors.Wrapf(err, "line %d", i+1)
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Прочитать справочник из json-массива объектов ImportRow
+func ReadImportJSON(r io.Reader) ([]ImportRow, error) {
+	var rows []ImportRow
+
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, errors.Wrap(err, "Error reading json")
+	}
+
+	for i := range rows {
+		rows[i].CategoryTitle = strings.TrimSpace(rows[i].CategoryTitle)
+		rows[i].Title = strings.TrimSpace(rows[i].Title)
+
+		if rows[i].DefaultUnit != nil {
+			unit := strings.TrimSpace(*rows[i].DefaultUnit)
+			rows[i].DefaultUnit = &unit
+		}
+	}
+
+	return rows, nil
+}
+
+// Заголовок распознается, если в строке есть колонки category и title
+func parseImportHeader(record []string) ([]string, bool) {
+	columns := make([]string, len(record))
+	found := make(map[string]bool)
+
+	for i, value := range record {
+		column := strings.ToLower(strings.TrimSpace(value))
+		if alias, ok := importColumnAliases[column]; ok {
+			column = alias
+		}
+
+		columns[i] = column
+		found[column] = true
+	}
+
+	return columns, found[importColumnCategory] && found[importColumnTitle]
+}
+
+// Заполнить перевод из колонки title_<язык> или category_<язык>. Остальные колонки игнорируются.
+// Пустое значение тоже сохраняется: перевод на этот язык будет удален
+func setImportTranslation(row *ImportRow, column string, value string) error {
+	i := strings.LastIndex(column, "_")
+	if i < 0 {
+		return nil
+	}
+
+	prefix, locale := column[:i], i18n.Normalize(column[i+1:])
+	if prefix != importColumnTitle && prefix != importColumnCategory {
+		return nil
+	}
+
+	if locale == "" {
+		return errors.New("unsupported locale in column " + column)
+	}
+
+	if prefix == importColumnTitle {
+		if row.Titles == nil {
+			row.Titles = make(map[string]string)
+		}
+
+		row.Titles[locale] = value
+	} else {
+		if row.CategoryTitles == nil {
+			row.CategoryTitles = make(map[string]string)
+		}
+
+		row.CategoryTitles[locale] = value
+	}
+
+	return nil
+}
+
+// Значения колонки. Для пустой колонки возвращается пустой список, а не nil: значения удаляются
+func splitImportValues(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, importValuesSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
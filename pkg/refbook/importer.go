@@ -0,0 +1,588 @@
+package refbook
+
+import (
+	"github.com/pkg/errors"
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/units"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	ImportEntityCategory = "category"
+	ImportEntityProduct  = "product"
+)
+
+const (
+	ImportActionAdd     = "add"
+	ImportActionRename  = "rename"
+	ImportActionMove    = "move"
+	ImportActionUpdate  = "update" // Изменились переводы, синонимы, штрихкоды или единица измерения
+	ImportActionRestore = "restore"
+	ImportActionRemove  = "remove"
+)
+
+// Строка импортируемого справочника.
+// Синонимы, штрихкоды и единица равны nil, если колонки или ключа нет в файле, тогда текущие значения не меняются.
+// Пустое значение из файла удаляет их
+type ImportRow struct {
+	ID            int64    `json:"id"` // Необязательный, позволяет переименовать товар
+	CategoryTitle string   `json:"category"`
+	Title         string   `json:"title"`
+	Synonyms      []string `json:"synonyms"`
+	Barcodes      []string `json:"barcodes"`
+	DefaultUnit   *string  `json:"unit"`
+
+	// Переводы названий: язык -> название. Основные названия указываются на языке по умолчанию.
+	// Меняются только переводы на языки из файла, пустое название удаляет перевод
+	Titles         map[string]string `json:"titles"`
+	CategoryTitles map[string]string `json:"category_titles"`
+}
+
+func (s *ImportRow) Validate() error {
+	if strings.TrimSpace(s.CategoryTitle) == "" {
+		return errors.New("category is empty")
+	}
+
+	if strings.TrimSpace(s.Title) == "" {
+		return errors.New("title is empty")
+	}
+
+	if s.DefaultUnit != nil && *s.DefaultUnit != "" && !units.IsValid(units.Unit(*s.DefaultUnit)) {
+		return errors.New("unknown unit: " + *s.DefaultUnit)
+	}
+
+	for _, barcode := range s.Barcodes {
+		if _, ok := NormalizeBarcode(barcode); !ok {
+			return errors.New("wrong barcode: " + barcode)
+		}
+	}
+
+	for _, titles := range []map[string]string{s.Titles, s.CategoryTitles} {
+		for locale := range titles {
+			if !i18n.IsSupported(locale) || locale == i18n.DefaultLocale {
+				return errors.New("wrong translation locale: " + locale)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Изменение справочника при импорте
+type ImportChange struct {
+	Entity      string
+	Action      string
+	ID          int64 // Для новых записей в режиме dry-run не настоящий
+	Title       string
+	OldTitle    string
+	Category    string
+	OldCategory string
+}
+
+type ImportDiff struct {
+	Changes []ImportChange
+}
+
+func (s *ImportDiff) add(change ImportChange) {
+	s.Changes = append(s.Changes, change)
+}
+
+// Количество изменений сущности с указанным действием
+func (s *ImportDiff) Count(entity string, action string) int {
+	count := 0
+	for _, change := range s.Changes {
+		if change.Entity == entity && change.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Импорт справочника без пересоздания записей.
+// Существующие категории и товары находятся по id или нормализованному названию и сохраняют id,
+// отсутствующие в файле помечаются удаленными
+type Importer struct {
+	categoriesRepository          repositories.RefbookCategoriesRepository
+	productsRepository            repositories.RefbookProductsRepository
+	synonymsRepository            repositories.RefbookSynonymsRepository
+	barcodesRepository            repositories.RefbookBarcodesRepository
+	categoryTranslationRepository repositories.RefbookTranslationsRepository
+	productTranslationRepository  repositories.RefbookTranslationsRepository
+}
+
+func NewImporter(db models.DB) *Importer {
+	return &Importer{
+		categoriesRepository:          repositories.NewRefbookCategoriesRepository(db),
+		productsRepository:            repositories.NewRefbookProductsRepository(db),
+		synonymsRepository:            repositories.NewRefbookSynonymsRepository(db),
+		barcodesRepository:            repositories.NewRefbookBarcodesRepository(db),
+		categoryTranslationRepository: repositories.NewRefbookCategoryTranslationsRepository(db),
+		productTranslationRepository:  repositories.NewRefbookProductTranslationsRepository(db)}
+}
+
+// Импортировать строки и вернуть список изменений.
+// Вызывать в транзакции, для dry-run транзакцию нужно откатить
+func (s *Importer) Import(rows []ImportRow) (ImportDiff, error) {
+	var diff ImportDiff
+
+	if err := validateImportRows(rows); err != nil {
+		return diff, err
+	}
+
+	categories, err := s.categoriesRepository.GetAllWithDeleted()
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			return diff, errors.Wrap(err, "Error get categories")
+		}
+	}
+
+	categoriesIds, err := s.importCategories(rows, categories, &diff)
+	if err != nil {
+		return diff, err
+	}
+
+	categoryTitles := make(map[int64]string)
+	for _, category := range categories {
+		categoryTitles[category.ID] = category.Title
+	}
+
+	err = s.importProducts(rows, categoriesIds, categoryTitles, &diff)
+	if err != nil {
+		return diff, err
+	}
+
+	return diff, nil
+}
+
+// Импортировать категории и вернуть их id по нормализованному названию
+func (s *Importer) importCategories(rows []ImportRow, categories []models.RefbookCategory, diff *ImportDiff) (map[string]int64, error) {
+	translations, err := s.categoryTranslationRepository.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]models.RefbookCategory)
+	for _, category := range categories {
+		key := NormalizeTitle(category.Title)
+		if exist, ok := exists[key]; ok && !exist.IsDeleted {
+			continue
+		}
+
+		exists[key] = category
+	}
+
+	// Переводы категории собираются со всех ее строк: перевод может быть указан не в первой
+	rowsTranslations := make(map[string]map[string]string)
+	for _, row := range rows {
+		key := NormalizeTitle(row.CategoryTitle)
+		if rowsTranslations[key] == nil {
+			rowsTranslations[key] = make(map[string]string)
+		}
+
+		for locale, title := range row.CategoryTitles {
+			if title = strings.TrimSpace(title); title != "" || rowsTranslations[key][locale] == "" {
+				rowsTranslations[key][locale] = title
+			}
+		}
+	}
+
+	ids := make(map[string]int64)
+	imported := make(map[int64]bool)
+
+	for _, row := range rows {
+		key := NormalizeTitle(row.CategoryTitle)
+		if _, ok := ids[key]; ok {
+			continue
+		}
+
+		category, ok := exists[key]
+		rowTranslations := mergeTranslations(translations[category.ID], rowsTranslations[key])
+
+		if !ok {
+			category = models.RefbookCategory{Title: row.CategoryTitle}
+
+			category.ID, err = s.categoriesRepository.Create(&category)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := s.categoryTranslationRepository.Replace(category.ID, rowTranslations); err != nil {
+				return nil, err
+			}
+
+			diff.add(ImportChange{Entity: ImportEntityCategory, Action: ImportActionAdd, ID: category.ID, Title: category.Title})
+		} else {
+			var changes []ImportChange
+
+			if category.IsDeleted {
+				changes = append(changes, ImportChange{Action: ImportActionRestore})
+			}
+
+			if category.Title != row.CategoryTitle {
+				changes = append(changes, ImportChange{Action: ImportActionRename, OldTitle: category.Title})
+			}
+
+			translationsChanged := !equalTranslations(translations[category.ID], rowTranslations)
+			if translationsChanged {
+				changes = append(changes, ImportChange{Action: ImportActionUpdate})
+			}
+
+			if len(changes) > 0 {
+				category.Title = row.CategoryTitle
+				if err := s.categoriesRepository.Update(&category); err != nil {
+					return nil, err
+				}
+
+				if translationsChanged {
+					if err := s.categoryTranslationRepository.Replace(category.ID, rowTranslations); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			for _, change := range changes {
+				change.Entity = ImportEntityCategory
+				change.ID = category.ID
+				change.Title = category.Title
+				diff.add(change)
+			}
+		}
+
+		ids[key] = category.ID
+		imported[category.ID] = true
+	}
+
+	for _, category := range categories {
+		if category.IsDeleted || imported[category.ID] {
+			continue
+		}
+
+		if err := s.categoriesRepository.Delete(category.ID); err != nil {
+			return nil, err
+		}
+
+		diff.add(ImportChange{Entity: ImportEntityCategory, Action: ImportActionRemove, ID: category.ID, Title: category.Title})
+	}
+
+	return ids, nil
+}
+
+func (s *Importer) importProducts(rows []ImportRow, categoriesIds map[string]int64, categoryTitles map[int64]string, diff *ImportDiff) error {
+	products, err := s.productsRepository.GetAllWithDeleted()
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			return errors.Wrap(err, "Error get products")
+		}
+	}
+
+	var list []models.RefbookProduct
+	if products != nil {
+		list = *products
+	}
+
+	synonyms, err := s.synonymsRepository.GetAll()
+	if err != nil {
+		return err
+	}
+
+	barcodes, err := s.barcodesRepository.GetAll()
+	if err != nil {
+		return err
+	}
+
+	translations, err := s.productTranslationRepository.GetAll()
+	if err != nil {
+		return err
+	}
+
+	byId := make(map[int64]models.RefbookProduct)
+	byKey := make(map[string]models.RefbookProduct)
+	for _, product := range list {
+		byId[product.ID] = product
+
+		key := NormalizeTitle(product.Title)
+		if exist, ok := byKey[key]; ok && !exist.IsDeleted {
+			continue
+		}
+
+		byKey[key] = product
+	}
+
+	imported := make(map[int64]bool)
+
+	for _, row := range rows {
+		categoryTitle := strings.TrimSpace(row.CategoryTitle)
+		categoryId := categoriesIds[NormalizeTitle(categoryTitle)]
+		categoryTitles[categoryId] = categoryTitle
+
+		product, ok := byId[row.ID]
+		if row.ID == 0 || !ok {
+			product, ok = byKey[NormalizeTitle(row.Title)]
+		}
+
+		if ok && imported[product.ID] {
+			return errors.New("product is duplicated in the file: " + row.Title)
+		}
+
+		// Для нового товара текущих значений нет: product.ID равен 0
+		rowSynonyms := uniqueValues(importedValues(row.Synonyms, synonyms[product.ID]), NormalizeTitle)
+		rowBarcodes := uniqueValues(normalizeBarcodes(importedValues(row.Barcodes, barcodes[product.ID])), strings.TrimSpace)
+		rowTranslations := mergeTranslations(translations[product.ID], row.Titles)
+
+		rowUnit := product.DefaultUnit
+		if row.DefaultUnit != nil {
+			rowUnit = *row.DefaultUnit
+		}
+
+		if !ok {
+			product = models.RefbookProduct{Title: row.Title, CategoryId: categoryId, DefaultUnit: rowUnit}
+
+			product.ID, err = s.productsRepository.Create(&product)
+			if err != nil {
+				return err
+			}
+
+			if err := s.saveExtras(product.ID, rowSynonyms, rowBarcodes, rowTranslations); err != nil {
+				return err
+			}
+
+			imported[product.ID] = true
+			diff.add(ImportChange{Entity: ImportEntityProduct, Action: ImportActionAdd, ID: product.ID,
+				Title: product.Title, Category: categoryTitle})
+			continue
+		}
+
+		imported[product.ID] = true
+
+		var changes []ImportChange
+
+		if product.IsDeleted {
+			changes = append(changes, ImportChange{Action: ImportActionRestore})
+		}
+
+		if product.Title != row.Title {
+			changes = append(changes, ImportChange{Action: ImportActionRename, OldTitle: product.Title})
+		}
+
+		if product.CategoryId != categoryId {
+			changes = append(changes, ImportChange{Action: ImportActionMove, OldCategory: categoryTitles[product.CategoryId]})
+		}
+
+		extrasChanged := !equalValues(uniqueValues(synonyms[product.ID], NormalizeTitle), rowSynonyms) ||
+			!equalValues(uniqueValues(barcodes[product.ID], strings.TrimSpace), rowBarcodes) ||
+			!equalTranslations(translations[product.ID], rowTranslations)
+		if extrasChanged || product.DefaultUnit != rowUnit {
+			changes = append(changes, ImportChange{Action: ImportActionUpdate})
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		product.Title = row.Title
+		product.CategoryId = categoryId
+		product.DefaultUnit = rowUnit
+
+		// Обновление меняет updated_at, поэтому клиенты получат и новые переводы, синонимы и штрихкоды
+		if err := s.productsRepository.Update(&product); err != nil {
+			return err
+		}
+
+		if extrasChanged {
+			if err := s.saveExtras(product.ID, rowSynonyms, rowBarcodes, rowTranslations); err != nil {
+				return err
+			}
+		}
+
+		for _, change := range changes {
+			change.Entity = ImportEntityProduct
+			change.ID = product.ID
+			change.Title = product.Title
+			change.Category = categoryTitle
+			diff.add(change)
+		}
+	}
+
+	for _, product := range list {
+		if product.IsDeleted || imported[product.ID] {
+			continue
+		}
+
+		// Синонимы и штрихкоды остаются, чтобы вернуться при восстановлении товара
+		if err := s.productsRepository.Delete(product.ID); err != nil {
+			return err
+		}
+
+		diff.add(ImportChange{Entity: ImportEntityProduct, Action: ImportActionRemove, ID: product.ID,
+			Title: product.Title, Category: categoryTitles[product.CategoryId]})
+	}
+
+	return nil
+}
+
+func (s *Importer) saveExtras(productId int64, synonyms []string, barcodes []string, translations map[string]string) error {
+	if err := s.synonymsRepository.Replace(productId, synonyms); err != nil {
+		return err
+	}
+
+	if err := s.barcodesRepository.Replace(productId, barcodes); err != nil {
+		return err
+	}
+
+	return s.productTranslationRepository.Replace(productId, translations)
+}
+
+// Проверить строки файла целиком до изменения базы
+func validateImportRows(rows []ImportRow) error {
+	barcodes := make(map[string]string)
+
+	for i, row := range rows {
+		if err := row.Validate(); err != nil {
+			return errors.Wrapf(err, "row %d", i+1)
+		}
+
+		for _, barcode := range row.Barcodes {
+			barcode, _ = NormalizeBarcode(barcode)
+			if title, ok := barcodes[barcode]; ok && NormalizeTitle(title) != NormalizeTitle(row.Title) {
+				return errors.Errorf("row %d: barcode %s is already used by %s", i+1, barcode, title)
+			}
+
+			barcodes[barcode] = row.Title
+		}
+	}
+
+	return nil
+}
+
+// Привести регистр названия: первая буква заглавная, название целиком в верхнем регистре переводится в нижний.
+// Остальные буквы не меняются, чтобы сохранить бренды и аббревиатуры
+func NormalizeTitleCase(title string) string {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return ""
+	}
+
+	if strings.ToUpper(title) == title {
+		title = strings.ToLower(title)
+	}
+
+	runes := []rune(title)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+// Нормализованное название для сопоставления: регистр, е/ё, пробелы и знаки препинания не учитываются
+func NormalizeTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '%' || r == '.' || r == ',' {
+			return r
+		}
+
+		return ' '
+	}, title)
+
+	words := strings.Fields(normalizeName(title))
+	result := words[:0]
+	for _, word := range words {
+		if word = strings.Trim(word, ".,"); word != "" {
+			result = append(result, word)
+		}
+	}
+
+	return strings.Join(result, " ")
+}
+
+// Значения без пустых и повторов, по ключу normalize, отсортированные
+func uniqueValues(values []string, normalize func(string) string) []string {
+	exists := make(map[string]bool)
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := normalize(value)
+		if key == "" || exists[key] {
+			continue
+		}
+
+		exists[key] = true
+		result = append(result, value)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Значения из файла или текущие, если колонки в файле нет
+func importedValues(values []string, current []string) []string {
+	if values == nil {
+		return current
+	}
+
+	return values
+}
+
+func equalValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Текущие переводы с переводами из файла. Переводы на языки, которых нет в файле, не меняются,
+// пустое название удаляет перевод
+func mergeTranslations(current map[string]string, imported map[string]string) map[string]string {
+	result := make(map[string]string)
+	for locale, title := range current {
+		result[locale] = title
+	}
+
+	for locale, title := range imported {
+		if title = strings.TrimSpace(title); title != "" {
+			result[locale] = title
+		} else {
+			delete(result, locale)
+		}
+	}
+
+	return result
+}
+
+func equalTranslations(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for locale, title := range a {
+		if b[locale] != title {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Штрихкоды в виде для хранения. Вызывается после проверки строк, поэтому ошибок нет
+func normalizeBarcodes(barcodes []string) []string {
+	result := make([]string, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		if normalized, ok := NormalizeBarcode(barcode); ok {
+			result = append(result, normalized)
+		}
+	}
+
+	return result
+}
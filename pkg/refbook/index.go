@@ -0,0 +1,69 @@
+package refbook
+
+import (
+	"github.com/pkg/errors"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"sync"
+	"time"
+)
+
+// Кэш сопоставителя товаров. Справочник меняется редко, поэтому перечитывается не чаще раза в ttl
+type Index struct {
+	repository         repositories.RefbookProductsRepository
+	synonymsRepository repositories.RefbookSynonymsRepository
+	ttl                time.Duration
+
+	mu       sync.Mutex
+	matcher  *Matcher
+	loadedAt time.Time
+}
+
+func NewIndex(repository repositories.RefbookProductsRepository,
+	synonymsRepository repositories.RefbookSynonymsRepository, ttl time.Duration) *Index {
+	return &Index{repository: repository, synonymsRepository: synonymsRepository, ttl: ttl}
+}
+
+// Вернуть сопоставитель, при необходимости перечитав справочник
+func (s *Index) Matcher() (*Matcher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.matcher != nil && time.Since(s.loadedAt) < s.ttl {
+		return s.matcher, nil
+	}
+
+	products, err := s.repository.GetAll()
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			return nil, errors.Wrap(err, "Error load refbook products")
+		}
+	}
+
+	var list []models.RefbookProduct
+	if products != nil {
+		list = *products
+	}
+
+	synonyms, err := s.synonymsRepository.GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "Error load refbook synonyms")
+	}
+
+	for i := range list {
+		list[i].Synonyms = synonyms[list[i].ID]
+	}
+
+	s.matcher = NewMatcher(list)
+	s.loadedAt = time.Now()
+
+	return s.matcher, nil
+}
+
+// Сбросить кэш, например после импорта справочника
+func (s *Index) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.matcher = nil
+}
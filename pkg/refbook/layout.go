@@ -0,0 +1,59 @@
+package refbook
+
+import "strings"
+
+const (
+	qwertyLayout = "qwertyuiop[]asdfghjkl;'zxcvbnm,.`"
+	jcukenLayout = "йцукенгшщзхъфывапролджэячсмитьбюё"
+)
+
+var qwertyToJcuken = buildLayoutMap(qwertyLayout, jcukenLayout)
+var jcukenToQwerty = buildLayoutMap(jcukenLayout, qwertyLayout)
+
+func buildLayoutMap(from string, to string) map[rune]rune {
+	result := make(map[rune]rune)
+
+	toRunes := []rune(to)
+	for i, r := range []rune(from) {
+		result[r] = toRunes[i]
+	}
+
+	return result
+}
+
+// Перевести строку, набранную в неправильной раскладке ("vjkjrj" -> "молоко" и обратно).
+// Направление выбирается по большинству букв. Возвращает false, если переводить нечего
+func SwitchLayout(value string) (string, bool) {
+	value = strings.ToLower(value)
+
+	var latin, cyrillic int
+	for _, r := range value {
+		if _, ok := qwertyToJcuken[r]; ok && r >= 'a' && r <= 'z' {
+			latin++
+		}
+
+		if _, ok := jcukenToQwerty[r]; ok {
+			cyrillic++
+		}
+	}
+
+	if latin == 0 && cyrillic == 0 {
+		return "", false
+	}
+
+	mapping := qwertyToJcuken
+	if cyrillic > latin {
+		mapping = jcukenToQwerty
+	}
+
+	var b strings.Builder
+	for _, r := range value {
+		if switched, ok := mapping[r]; ok {
+			b.WriteRune(switched)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String(), true
+}
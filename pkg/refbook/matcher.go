@@ -0,0 +1,236 @@
+package refbook
+
+import (
+	"math"
+	"shopingList/pkg/models"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Минимальная уверенность, при которой товар считается сопоставленным
+const MinConfidence = 0.5
+
+// Результат сопоставления названия товара со справочником
+type Match struct {
+	ProductID  int64   `json:"product_id"`
+	CategoryID int64   `json:"category_id"`
+	Title      string  `json:"title"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Название или синоним товара справочника
+type indexedProduct struct {
+	product   models.RefbookProduct
+	tokens    []string
+	isSynonym bool
+}
+
+// Сопоставление произвольных названий товаров с товарами справочника.
+// Синонимы индексируются как отдельные названия, но сопоставляются с основным товаром
+type Matcher struct {
+	products []indexedProduct
+	exact    map[string]int
+	prefixes map[string][]int
+	byId     map[int64]int
+}
+
+func NewMatcher(products []models.RefbookProduct) *Matcher {
+	m := &Matcher{
+		exact:    make(map[string]int),
+		prefixes: make(map[string][]int),
+		byId:     make(map[int64]int),
+	}
+
+	for _, product := range products {
+		ind, ok := m.add(product, product.Title, false)
+		if !ok {
+			continue
+		}
+
+		m.byId[product.ID] = ind
+
+		for _, synonym := range product.Synonyms {
+			m.add(product, synonym, true)
+		}
+	}
+
+	return m
+}
+
+func (m *Matcher) add(product models.RefbookProduct, title string, isSynonym bool) (int, bool) {
+	tokens := Tokenize(title)
+	if len(tokens) == 0 {
+		return 0, false
+	}
+
+	ind := len(m.products)
+	m.products = append(m.products, indexedProduct{product: product, tokens: tokens, isSynonym: isSynonym})
+
+	// Совпадение с основным названием важнее совпадения с синонимом другого товара
+	key := strings.Join(tokens, " ")
+	if exist, ok := m.exact[key]; !ok || (m.products[exist].isSynonym && !isSynonym) {
+		m.exact[key] = ind
+	}
+
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		prefix := tokenPrefix(token)
+		if !seen[prefix] {
+			m.prefixes[prefix] = append(m.prefixes[prefix], ind)
+			seen[prefix] = true
+		}
+	}
+
+	return ind, true
+}
+
+// Вернуть товар справочника по ID
+func (m *Matcher) Product(id int64) (models.RefbookProduct, bool) {
+	ind, ok := m.byId[id]
+	if !ok {
+		return models.RefbookProduct{}, false
+	}
+
+	return m.products[ind].product, true
+}
+
+// Найти наиболее подходящий товар справочника для названия.
+// Все слова названия товара справочника должны встречаться в названии, с учетом окончаний
+func (m *Matcher) Match(name string) (Match, bool) {
+	tokens := Tokenize(name)
+	if len(tokens) == 0 {
+		return Match{}, false
+	}
+
+	if ind, ok := m.exact[strings.Join(tokens, " ")]; ok {
+		return m.newMatch(ind, 1), true
+	}
+
+	best := -1
+	var bestConfidence float64
+
+	checked := make(map[int]bool)
+	for _, token := range tokens {
+		for _, ind := range m.prefixes[tokenPrefix(token)] {
+			if checked[ind] {
+				continue
+			}
+			checked[ind] = true
+
+			confidence := score(m.products[ind].tokens, tokens)
+			if confidence > bestConfidence ||
+				(confidence == bestConfidence && best >= 0 && len(m.products[ind].tokens) > len(m.products[best].tokens)) {
+				best = ind
+				bestConfidence = confidence
+			}
+		}
+	}
+
+	if best < 0 || bestConfidence < MinConfidence {
+		return Match{}, false
+	}
+
+	return m.newMatch(best, bestConfidence), true
+}
+
+func (m *Matcher) newMatch(ind int, confidence float64) Match {
+	product := m.products[ind].product
+
+	return Match{ProductID: product.ID, CategoryID: product.CategoryId, Title: product.Title,
+		Confidence: math.Round(confidence*1000) / 1000}
+}
+
+// Разбить название на нормализованные слова.
+// Числа и однобуквенные слова (объем, жирность, единицы измерения) не участвуют в сопоставлении
+func Tokenize(value string) []string {
+	value = strings.ReplaceAll(strings.ToLower(value), "ё", "е")
+
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if utf8.RuneCountInString(field) < 2 || isNumber(field) {
+			continue
+		}
+
+		tokens = append(tokens, field)
+	}
+
+	return tokens
+}
+
+// Уверенность сопоставления: средняя похожесть слов товара справочника,
+// уменьшенная за слова названия, которых нет в справочнике
+func score(productTokens []string, nameTokens []string) float64 {
+	var sum float64
+
+	for _, productToken := range productTokens {
+		var best float64
+		for _, nameToken := range nameTokens {
+			if s := tokenScore(productToken, nameToken); s > best {
+				best = s
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+
+		sum += best
+	}
+
+	coverage := float64(len(productTokens)) / float64(len(nameTokens))
+	if coverage > 1 {
+		coverage = 1
+	}
+
+	return sum / float64(len(productTokens)) * (0.6 + 0.4*coverage)
+}
+
+// Похожесть слов. Слова с общей основой и разными окончаниями ("яблоко", "яблоки") считаются похожими
+func tokenScore(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra := []rune(a)
+	rb := []rune(b)
+
+	common := 0
+	for common < len(ra) && common < len(rb) && ra[common] == rb[common] {
+		common++
+	}
+
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+
+	if common >= 3 && len(ra) >= 4 && len(rb) >= 4 && maxLen-common <= 2 {
+		return 0.8
+	}
+
+	return 0
+}
+
+func tokenPrefix(token string) string {
+	runes := []rune(token)
+	if len(runes) > 3 {
+		runes = runes[:3]
+	}
+
+	return string(runes)
+}
+
+func isNumber(value string) bool {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
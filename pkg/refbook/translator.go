@@ -0,0 +1,58 @@
+package refbook
+
+import (
+	"shopingList/pkg/i18n"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+)
+
+// Перевод названий справочника. Если перевода нет, остается название на языке по умолчанию
+type Translator struct {
+	categoryTranslationsRepository repositories.RefbookTranslationsRepository
+	productTranslationsRepository  repositories.RefbookTranslationsRepository
+}
+
+func NewTranslator(categoryTranslationsRepository repositories.RefbookTranslationsRepository,
+	productTranslationsRepository repositories.RefbookTranslationsRepository) *Translator {
+	return &Translator{
+		categoryTranslationsRepository: categoryTranslationsRepository,
+		productTranslationsRepository:  productTranslationsRepository}
+}
+
+func (s *Translator) TranslateCategories(locale string, categories []models.RefbookCategory) error {
+	if locale == i18n.DefaultLocale || len(categories) == 0 {
+		return nil
+	}
+
+	titles, err := s.categoryTranslationsRepository.GetForLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	for i := range categories {
+		if title, ok := titles[categories[i].ID]; ok {
+			categories[i].Title = title
+		}
+	}
+
+	return nil
+}
+
+func (s *Translator) TranslateProducts(locale string, products []models.RefbookProduct) error {
+	if locale == i18n.DefaultLocale || len(products) == 0 {
+		return nil
+	}
+
+	titles, err := s.productTranslationsRepository.GetForLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		if title, ok := titles[products[i].ID]; ok {
+			products[i].Title = title
+		}
+	}
+
+	return nil
+}
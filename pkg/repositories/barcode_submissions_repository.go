@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type BarcodeSubmissionsRepository struct {
+	db models.DB
+}
+
+func NewBarcodeSubmissionsRepository(db models.DB) BarcodeSubmissionsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return BarcodeSubmissionsRepository{db: db}
+}
+
+// Сохранить привязку штрихкода. Повторная привязка пользователем заменяет предыдущую
+func (s *BarcodeSubmissionsRepository) Save(submission *models.BarcodeSubmission) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.BarcodeSubmissionTableName+` (
+                    barcode, user_id, user_product_id, name, category_id) 
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			user_product_id = VALUES(user_product_id), 
+			name = VALUES(name), 
+			category_id = VALUES(category_id), 
+			updated_at = NOW()`,
+		submission.Barcode, submission.UserID, submission.UserProductID, submission.Name, submission.CategoryID)
+
+	if err != nil {
+		return errors.New("Error save barcode submission; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *BarcodeSubmissionsRepository) GetForUser(barcode string, userId string) (models.BarcodeSubmission, error) {
+	var m models.BarcodeSubmission
+
+	err := s.db.QueryRow(
+		`SELECT barcode,
+				user_id,
+				user_product_id,
+				name,
+				category_id,
+				UNIX_TIMESTAMP(created_at),
+				UNIX_TIMESTAMP(updated_at)
+		FROM `+models.BarcodeSubmissionTableName+`
+		WHERE barcode = ? AND user_id = ?`,
+		barcode, userId).
+		Scan(&m.Barcode, &m.UserID, &m.UserProductID, &m.Name, &m.CategoryID, &m.CreatedAt, &m.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return m, ErrNotFound{}
+		}
+
+		return m, err
+	}
+
+	return m, nil
+}
+
+// Вернуть штрихкоды, которых нет в справочнике, по убыванию количества пользователей
+func (s *BarcodeSubmissionsRepository) GetTop(limit int) ([]models.BarcodeSubmissionStat, error) {
+	rows, err := s.db.Query(
+		`SELECT s.barcode,
+				MAX(s.name),
+				MAX(s.category_id),
+				COUNT(*) AS cnt
+		FROM `+models.BarcodeSubmissionTableName+` AS s
+		LEFT JOIN `+models.RefbookBarcodeTableName+` AS b ON (b.barcode = s.barcode)
+		WHERE b.barcode IS NULL
+		GROUP BY s.barcode
+		ORDER BY cnt DESC, s.barcode
+		LIMIT ?`,
+		limit)
+	if err != nil {
+		return nil, errors.New("Error get barcode submissions; " + err.Error())
+	}
+	defer rows.Close()
+
+	var stats []models.BarcodeSubmissionStat
+
+	for rows.Next() {
+		var m models.BarcodeSubmissionStat
+		if err := rows.Scan(&m.Barcode, &m.Name, &m.CategoryID, &m.UsersCount); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, m)
+	}
+
+	return stats, rows.Err()
+}
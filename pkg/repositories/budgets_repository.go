@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type BudgetsRepository struct {
+	db models.DB
+}
+
+func NewBudgetsRepository(db models.DB) BudgetsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return BudgetsRepository{db: db}
+}
+
+// Создать или обновить бюджет
+func (s *BudgetsRepository) Save(budget *models.Budget) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.BudgetTableName+` (
+                    owner_id, list_id, amount, currency, notified_at, created_at, updated_at, is_deleted
+                    ) 
+		VALUES (?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?), ?)
+		ON DUPLICATE KEY UPDATE 
+		    amount=VALUES(amount), currency=VALUES(currency), notified_at=VALUES(notified_at), 
+		    updated_at=VALUES(updated_at), is_deleted=VALUES(is_deleted)`,
+		budget.OwnerID, budget.ListID, budget.Amount, budget.Currency, nullableTimestamp(budget.NotifiedAt),
+		budget.CreatedAt, budget.UpdatedAt, budget.IsDeleted)
+
+	if err != nil {
+		return errors.New("Error save budget; " + err.Error())
+	}
+
+	return nil
+}
+
+// Запомнить время уведомления о превышении бюджета. 0 - сбросить
+func (s *BudgetsRepository) SetNotifiedAt(ownerId string, listId string, notifiedAt int64) error {
+	_, err := s.db.Exec(`UPDATE `+models.BudgetTableName+` SET notified_at = FROM_UNIXTIME(?) 
+		WHERE owner_id = ? AND list_id = ?`,
+		nullableTimestamp(notifiedAt), ownerId, listId)
+
+	if err != nil {
+		return errors.New("Error update budget notified_at; " + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type ContactAliasesRepository struct {
+	db models.DB
+}
+
+func NewContactAliasesRepository(db models.DB) ContactAliasesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return ContactAliasesRepository{db: db}
+}
+
+func (s *ContactAliasesRepository) Save(userId string, alias *models.ContactAlias) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.ContactAliasTableName+` (
+                    user_id, contact_user_id, alias) 
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			alias = VALUES(alias), 
+			updated_at = NOW()`,
+		userId, alias.UserId, alias.Alias)
+
+	if err != nil {
+		return errors.New("Error save contact alias; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *ContactAliasesRepository) Delete(userId string, contactUserId string) error {
+	_, err := s.db.Exec(`DELETE FROM `+models.ContactAliasTableName+` WHERE user_id = ? AND contact_user_id = ?`,
+		userId, contactUserId)
+
+	if err != nil {
+		return errors.New("Error delete contact alias; " + err.Error())
+	}
+
+	return nil
+}
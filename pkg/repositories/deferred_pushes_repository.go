@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+	"strings"
+)
+
+type DeferredPushesRepository struct {
+	db models.DB
+}
+
+func NewDeferredPushesRepository(db models.DB) DeferredPushesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return DeferredPushesRepository{db: db}
+}
+
+func (s *DeferredPushesRepository) Add(model *models.DeferredPush) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.DeferredPushTableName+` (
+                    target_user_id, type, message, user_id, user_phone, list_id, item_id, release_at
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?))`,
+		model.TargetUserId, model.TypeNotification, model.Message, model.UserId, model.UserPhone,
+		model.ListId, model.ItemId, model.ReleaseAt)
+
+	if err != nil {
+		return errors.New("Error insert deferred push; " + err.Error())
+	}
+
+	return nil
+}
+
+// Удалить отправленные push
+func (s *DeferredPushesRepository) Delete(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	_, err := s.db.Exec(`DELETE FROM `+models.DeferredPushTableName+` 
+		WHERE id IN (?`+strings.Repeat(`,?`, len(ids)-1)+`)`, args...)
+
+	if err != nil {
+		return errors.New("Error delete deferred pushes; " + err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package repositories
+
+// Значение для FROM_UNIXTIME(), 0 сохраняется как NULL
+func nullableTimestamp(value int64) interface{} {
+	if value == 0 {
+		return nil
+	}
+
+	return value
+}
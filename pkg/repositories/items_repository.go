@@ -25,6 +25,13 @@ func (s *ItemsRepository) GetItem(id string, listId string) (models.ListItem, er
 		`SELECT id,
        			name, 
        			value,
+       			quantity,
+       			unit,
+       			price,
+       			currency,
+       			product_id,
+       			category_id,
+       			match_confidence,
        			is_marked, 
        			user_marked_id,
        			list_id, 
@@ -36,7 +43,9 @@ func (s *ItemsRepository) GetItem(id string, listId string) (models.ListItem, er
 
 	var i models.ListItem
 	err := row.Scan(
-		&i.ID, &i.Name, &i.Value, &i.IsMarked, &i.UserMarked, &i.ListID, &i.IsDeleted, &i.CreatedAt, &i.UpdatedAt,
+		&i.ID, &i.Name, &i.Value, &i.Quantity, &i.Unit, &i.Price, &i.Currency,
+		&i.ProductID, &i.CategoryID, &i.MatchConfidence,
+		&i.IsMarked, &i.UserMarked, &i.ListID, &i.IsDeleted, &i.CreatedAt, &i.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -52,10 +61,13 @@ func (s *ItemsRepository) CreateItem(item *models.ListItem) error {
 	v := item.UserMarked.SqlValue()
 
 	_, err := s.db.Exec(`INSERT INTO sl_item (
-                    id, name, value, is_marked, user_marked_id, list_id, is_deleted, created_at, updated_at, received_at
+                    id, name, value, quantity, unit, price, currency, product_id, category_id, match_confidence, 
+                    is_marked, user_marked_id, list_id, is_deleted, created_at, updated_at, received_at
                     ) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?))`,
-		item.ID, item.Name, item.Value, item.IsMarked, v, item.ListID, item.IsDeleted,
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?))`,
+		item.ID, item.Name, item.Value, item.Quantity.SqlValue(), item.Unit, item.Price.SqlValue(), item.Currency,
+		item.ProductID.SqlValue(), item.CategoryID.SqlValue(), item.MatchConfidence.SqlValue(),
+		item.IsMarked, v, item.ListID, item.IsDeleted,
 		item.CreatedAt, item.UpdatedAt, item.ReceivedAt)
 
 	if err != nil {
@@ -67,9 +79,13 @@ func (s *ItemsRepository) CreateItem(item *models.ListItem) error {
 
 func (s *ItemsRepository) UpdateItem(item *models.ListItem) error {
 	_, err := s.db.Exec(`UPDATE sl_item 
-		SET name=?, value=?, is_marked=?, user_marked_id=?, is_deleted=?, updated_at=FROM_UNIXTIME(?), received_at=FROM_UNIXTIME(?)
+		SET name=?, value=?, quantity=?, unit=?, price=?, currency=?, product_id=?, category_id=?, match_confidence=?, 
+		    is_marked=?, user_marked_id=?, is_deleted=?, 
+		    updated_at=FROM_UNIXTIME(?), received_at=FROM_UNIXTIME(?)
 		WHERE id=? AND list_id=?`,
-		item.Name, item.Value, item.IsMarked, item.UserMarked.SqlValue(), item.IsDeleted, item.UpdatedAt, item.ReceivedAt,
+		item.Name, item.Value, item.Quantity.SqlValue(), item.Unit, item.Price.SqlValue(), item.Currency,
+		item.ProductID.SqlValue(), item.CategoryID.SqlValue(), item.MatchConfidence.SqlValue(),
+		item.IsMarked, item.UserMarked.SqlValue(), item.IsDeleted, item.UpdatedAt, item.ReceivedAt,
 		item.ID, item.ListID)
 
 	if err != nil {
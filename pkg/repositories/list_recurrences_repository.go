@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type ListRecurrencesRepository struct {
+	db models.DB
+}
+
+func NewListRecurrencesRepository(db models.DB) ListRecurrencesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return ListRecurrencesRepository{db: db}
+}
+
+// Создать или обновить правило повторения списка
+func (s *ListRecurrencesRepository) Save(recurrence *models.ListRecurrence) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.ListRecurrenceTableName+` (
+                    list_id, owner_id, type, interval_days, week_day, month_day, hour, 
+                    next_run_at, last_run_at, created_at, updated_at, is_deleted
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?), ?)
+		ON DUPLICATE KEY UPDATE 
+		    type=VALUES(type), interval_days=VALUES(interval_days), week_day=VALUES(week_day), 
+		    month_day=VALUES(month_day), hour=VALUES(hour), next_run_at=VALUES(next_run_at), 
+		    last_run_at=VALUES(last_run_at), updated_at=VALUES(updated_at), is_deleted=VALUES(is_deleted)`,
+		recurrence.ListID, recurrence.OwnerID, recurrence.Type, recurrence.IntervalDays, recurrence.WeekDay,
+		recurrence.MonthDay, recurrence.Hour, recurrence.NextRunAt, nullableTimestamp(recurrence.LastRunAt),
+		recurrence.CreatedAt, recurrence.UpdatedAt, recurrence.IsDeleted)
+
+	if err != nil {
+		return errors.New("Error save list recurrence; " + err.Error())
+	}
+
+	return nil
+}
@@ -27,6 +27,10 @@ func (s *ListsRepository) CreateList(list *models.List) error {
 		VALUES (?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?), ?)`,
 		list.ID, list.OwnerID, list.Name, list.IsTemplate, list.CreatedAt, list.UpdatedAt, list.ReceivedAt, list.IsDeleted)
 
+	if isDuplicateKeyError(err) {
+		return ErrDuplicate{}
+	}
+
 	if err != nil {
 		return errors.New("Error insert list; " + err.Error())
 	}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type NotificationPreferencesRepository struct {
+	db models.DB
+}
+
+func NewNotificationPreferencesRepository(db models.DB) NotificationPreferencesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return NotificationPreferencesRepository{db: db}
+}
+
+func (s *NotificationPreferencesRepository) Save(userId string, preference *models.NotificationPreference) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.NotificationPreferenceTableName+` (
+                    user_id, type, channel, email) 
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			channel = VALUES(channel), 
+			email = VALUES(email), 
+			updated_at = NOW()`,
+		userId, preference.Type, preference.Channel, preference.Email)
+
+	if err != nil {
+		return errors.New("Error save notification preference; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *NotificationPreferencesRepository) SaveListMute(userId string, mute *models.NotificationListMute) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.NotificationListMuteTableName+` (
+                    user_id, list_id, muted_until) 
+		VALUES (?, ?, FROM_UNIXTIME(?))
+		ON DUPLICATE KEY UPDATE 
+			muted_until = VALUES(muted_until)`,
+		userId, mute.ListID, nullableTimestamp(mute.MutedUntil.Int64))
+
+	if err != nil {
+		return errors.New("Error save list mute; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *NotificationPreferencesRepository) DeleteListMute(userId string, listId string) error {
+	_, err := s.db.Exec(`DELETE FROM `+models.NotificationListMuteTableName+` WHERE user_id = ? AND list_id = ?`,
+		userId, listId)
+
+	if err != nil {
+		return errors.New("Error delete list mute; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *NotificationPreferencesRepository) SaveSettings(userId string, settings *models.NotificationSettings) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.NotificationSettingsTableName+` (
+                    user_id, timezone, locale, quiet_start, quiet_end, quiet_mode, weekly_email) 
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			timezone = VALUES(timezone), 
+			locale = VALUES(locale), 
+			quiet_start = VALUES(quiet_start), 
+			quiet_end = VALUES(quiet_end), 
+			quiet_mode = VALUES(quiet_mode), 
+			weekly_email = VALUES(weekly_email), 
+			updated_at = NOW()`,
+		userId, settings.Timezone, settings.Locale, settings.QuietStart.SqlValue(), settings.QuietEnd.SqlValue(),
+		settings.QuietMode, settings.WeeklyEmail)
+
+	if err != nil {
+		return errors.New("Error save notification settings; " + err.Error())
+	}
+
+	return nil
+}
+
+// Отметить отправку еженедельной сводки
+func (s *NotificationPreferencesRepository) MarkWeeklyEmailSent(userId string, sentAt int64) error {
+	_, err := s.db.Exec(`UPDATE `+models.NotificationSettingsTableName+` 
+		SET weekly_email_sent_at = FROM_UNIXTIME(?), weekly_email_failures = 0 
+		WHERE user_id = ?`,
+		sentAt, userId)
+
+	if err != nil {
+		return errors.New("Error mark weekly email sent; " + err.Error())
+	}
+
+	return nil
+}
+
+// Учесть неудачную отправку еженедельной сводки. После maxFailures неудач сводка за эту неделю
+// считается отправленной, чтобы адрес с постоянной ошибкой не повторялся бесконечно
+func (s *NotificationPreferencesRepository) MarkWeeklyEmailFailed(userId string, failedAt int64, maxFailures int) error {
+	_, err := s.db.Exec(`UPDATE `+models.NotificationSettingsTableName+` 
+		SET weekly_email_sent_at = IF(weekly_email_failures + 1 >= ?, FROM_UNIXTIME(?), weekly_email_sent_at), 
+			weekly_email_failures = IF(weekly_email_failures + 1 >= ?, 0, weekly_email_failures + 1) 
+		WHERE user_id = ?`,
+		maxFailures, failedAt, maxFailures, userId)
+
+	if err != nil {
+		return errors.New("Error mark weekly email failed; " + err.Error())
+	}
+
+	return nil
+}
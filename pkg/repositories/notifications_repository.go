@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"shopingList/pkg/models"
+	"strings"
 )
 
 type NotificationsRepository struct {
@@ -23,11 +24,14 @@ func (s *NotificationsRepository) GetById(id string) (models.Notification, error
 		`SELECT id,
        			type, 
        			message,
+       			template,
+       			params,
        			user_id, 
        			user_phone,
        			list_id, 
        			item_id, 
 				target_user_id,
+				UNIX_TIMESTAMP(read_at),
        			UNIX_TIMESTAMP(created_at) 
 		FROM `+models.NotificationTableName+`
 		WHERE id = ?`, id)
@@ -35,8 +39,8 @@ func (s *NotificationsRepository) GetById(id string) (models.Notification, error
 	var model models.Notification
 
 	err := row.Scan(
-		&model.ID, &model.TypeNotification, &model.Message, &model.UserId, &model.UserPhone,
-		&model.ListId, &model.ItemId, &model.TargetUserId, &model.CreatedAt,
+		&model.ID, &model.TypeNotification, &model.Message, &model.Template, &model.Params, &model.UserId, &model.UserPhone,
+		&model.ListId, &model.ItemId, &model.TargetUserId, &model.ReadAt, &model.CreatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -46,15 +50,17 @@ func (s *NotificationsRepository) GetById(id string) (models.Notification, error
 		return models.Notification{}, err
 	}
 
+	model.IsRead = model.ReadAt.Valid
+
 	return model, nil
 }
 
 func (s *NotificationsRepository) Create(form *models.NotificationCreateForm) error {
 	_, err := s.db.Exec(`INSERT INTO `+models.NotificationTableName+` (
-                    id, type, message, user_id, user_phone, list_id, item_id, target_user_id
+                    id, type, message, template, params, user_id, user_phone, list_id, item_id, target_user_id
                     ) 
-		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?)`,
-		form.TypeNotification, form.Message, form.UserId, form.UserPhone, form.ListId, form.ItemId.SqlValue(), form.TargetUserId)
+		VALUES (UUID(), ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		form.TypeNotification, form.Message, form.Template, form.Params, form.UserId, form.UserPhone, form.ListId, form.ItemId.SqlValue(), form.TargetUserId)
 
 	if err != nil {
 		return errors.New("Error insert notification; " + err.Error())
@@ -62,3 +68,69 @@ func (s *NotificationsRepository) Create(form *models.NotificationCreateForm) er
 
 	return nil
 }
+
+// Отметить уведомления пользователя прочитанными. Возвращает количество измененных
+func (s *NotificationsRepository) MarkRead(targetUserId string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	args := []interface{}{targetUserId}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	result, err := s.db.Exec(`UPDATE `+models.NotificationTableName+` 
+		SET read_at = NOW(), updated_at = NOW() 
+		WHERE target_user_id = ? AND read_at IS NULL AND is_deleted = 0 
+			AND id IN (?`+strings.Repeat(`,?`, len(ids)-1)+`)`,
+		args...)
+
+	if err != nil {
+		return 0, errors.New("Error mark notifications as read; " + err.Error())
+	}
+
+	return result.RowsAffected()
+}
+
+// Отметить прочитанными все уведомления пользователя
+func (s *NotificationsRepository) MarkAllRead(targetUserId string) (int64, error) {
+	result, err := s.db.Exec(`UPDATE `+models.NotificationTableName+` 
+		SET read_at = NOW(), updated_at = NOW() 
+		WHERE target_user_id = ? AND read_at IS NULL AND is_deleted = 0`,
+		targetUserId)
+
+	if err != nil {
+		return 0, errors.New("Error mark all notifications as read; " + err.Error())
+	}
+
+	return result.RowsAffected()
+}
+
+// Удалить уведомление пользователя. Запись остается, чтобы удаление попало на другие устройства
+func (s *NotificationsRepository) Delete(targetUserId string, id string) (int64, error) {
+	result, err := s.db.Exec(`UPDATE `+models.NotificationTableName+` 
+		SET is_deleted = 1, updated_at = NOW() 
+		WHERE target_user_id = ? AND id = ? AND is_deleted = 0`,
+		targetUserId, id)
+
+	if err != nil {
+		return 0, errors.New("Error delete notification; " + err.Error())
+	}
+
+	return result.RowsAffected()
+}
+
+// Удалить из базы уведомления старше before, не больше limit за раз, чтобы не блокировать таблицу надолго
+func (s *NotificationsRepository) DeleteOlderThan(before int64, limit int) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM `+models.NotificationTableName+` 
+		WHERE created_at < FROM_UNIXTIME(?) 
+		LIMIT ?`,
+		before, limit)
+
+	if err != nil {
+		return 0, errors.New("Error delete old notifications; " + err.Error())
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+	"strings"
+)
+
+type PendingNotificationsRepository struct {
+	db models.DB
+}
+
+func NewPendingNotificationsRepository(db models.DB) PendingNotificationsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return PendingNotificationsRepository{db: db}
+}
+
+func (s *PendingNotificationsRepository) Add(model *models.PendingNotification) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.PendingNotificationTableName+` (
+                    target_user_id, user_id, user_name, user_phone, list_id, list_name, 
+                    item_id, item_name, type, push, is_digest
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		model.TargetUserId, model.UserId, model.UserName, model.UserPhone, model.ListId, model.ListName,
+		model.ItemId, model.ItemName, model.TypeNotification, model.Push, model.IsDigest)
+
+	if err != nil {
+		return errors.New("Error insert pending notification; " + err.Error())
+	}
+
+	return nil
+}
+
+// Удалить отправленные события
+func (s *PendingNotificationsRepository) Delete(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	_, err := s.db.Exec(`DELETE FROM `+models.PendingNotificationTableName+` 
+		WHERE id IN (?`+strings.Repeat(`,?`, len(ids)-1)+`)`, args...)
+
+	if err != nil {
+		return errors.New("Error delete pending notifications; " + err.Error())
+	}
+
+	return nil
+}
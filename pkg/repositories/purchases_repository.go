@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type PurchasesRepository struct {
+	db models.DB
+}
+
+func NewPurchasesRepository(db models.DB) PurchasesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return PurchasesRepository{db: db}
+}
+
+// Записать покупку. Повторная запись той же версии товара игнорируется, тогда возвращается false
+func (s *PurchasesRepository) Create(purchase *models.Purchase) (bool, error) {
+	result, err := s.db.Exec(`INSERT IGNORE INTO `+models.PurchaseTableName+` (
+                    id, trip_id, list_id, item_id, user_id, store_id, name, quantity, unit, price, currency, 
+                    product_id, category_id, item_updated_at, purchased_at
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?))`,
+		purchase.ID, purchase.TripID, purchase.ListID, purchase.ItemID, purchase.UserID, purchase.StoreID.SqlValue(),
+		purchase.Name, purchase.Quantity.SqlValue(), purchase.Unit, purchase.Price.SqlValue(), purchase.Currency,
+		purchase.ProductID.SqlValue(), purchase.CategoryID.SqlValue(), purchase.ItemUpdatedAt, purchase.PurchasedAt)
+
+	if err != nil {
+		return false, errors.New("Error insert purchase; " + err.Error())
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
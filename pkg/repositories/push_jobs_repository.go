@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"shopingList/pkg/models"
+)
+
+// Задание захвачено другим обработчиком после истечения захвата
+var ErrPushJobLockLost = errors.New("push job lock is lost")
+
+type PushJobsRepository struct {
+	db models.DB
+}
+
+func NewPushJobsRepository(db models.DB) PushJobsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return PushJobsRepository{db: db}
+}
+
+const pushJobFields = `id, target_user_id, type, message, user_id, user_phone, list_id, item_id, silent, tokens, attempts, last_error, 
+		UNIX_TIMESTAMP(next_attempt_at), UNIX_TIMESTAMP(created_at)`
+
+func (s *PushJobsRepository) Add(job *models.PushJob) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.PushJobTableName+` (
+                    target_user_id, type, message, user_id, user_phone, list_id, item_id, silent, tokens
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.TargetUserId, job.TypeNotification, job.Message, job.UserId, job.UserPhone, job.ListId, job.ItemId, job.Silent,
+		job.Tokens)
+
+	if err != nil {
+		return errors.New("Error insert push job; " + err.Error())
+	}
+
+	return nil
+}
+
+// Захватить задания, которые пора отправить. Захват действует lease секунд,
+// после этого задание снова доступно, например если обработчик упал
+func (s *PushJobsRepository) Claim(now int64, limit int, lease int64) ([]models.PushJob, error) {
+	lockToken := uuid.New().String()
+
+	_, err := s.db.Exec(`UPDATE `+models.PushJobTableName+` 
+		SET lock_token = ?, locked_until = FROM_UNIXTIME(?)
+		WHERE next_attempt_at <= FROM_UNIXTIME(?) AND (locked_until IS NULL OR locked_until < FROM_UNIXTIME(?))
+		ORDER BY next_attempt_at, id
+		LIMIT ?`,
+		lockToken, now+lease, now, now, limit)
+	if err != nil {
+		return nil, errors.New("Error claim push jobs; " + err.Error())
+	}
+
+	rows, err := s.db.Query(`SELECT `+pushJobFields+` FROM `+models.PushJobTableName+` WHERE lock_token = ? ORDER BY id`,
+		lockToken)
+	if err != nil {
+		return nil, errors.New("Error get claimed push jobs; " + err.Error())
+	}
+
+	jobs, err := scanPushJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		jobs[i].LockToken = lockToken
+	}
+
+	return jobs, nil
+}
+
+// Запланировать повторную отправку и снять захват.
+// Изменения задания возможны только с токеном захвата, иначе возвращается ErrPushJobLockLost
+func (s *PushJobsRepository) Reschedule(job *models.PushJob) error {
+	result, err := s.db.Exec(`UPDATE `+models.PushJobTableName+` 
+		SET attempts = ?, last_error = ?, tokens = ?, next_attempt_at = FROM_UNIXTIME(?), 
+			locked_until = NULL, lock_token = NULL
+		WHERE id = ? AND lock_token = ?`,
+		job.Attempts, truncateError(job.LastError), job.Tokens, job.NextAttemptAt, job.ID, job.LockToken)
+
+	if err != nil {
+		return errors.New("Error reschedule push job; " + err.Error())
+	}
+
+	return checkPushJobLock(result)
+}
+
+func (s *PushJobsRepository) Delete(job *models.PushJob) error {
+	return deletePushJob(s.db, job)
+}
+
+// Перенести задание, исчерпавшее попытки, в dead letter
+func (s *PushJobsRepository) MoveToDeadLetter(job *models.PushJob) error {
+	return s.inTransaction(func(db models.DB) error {
+		if err := deletePushJob(db, job); err != nil {
+			return err
+		}
+
+		return insertPushDeadLetter(db, job)
+	})
+}
+
+// Вернуть задание из dead letter в очередь. Попытки считаются заново, токены берутся текущие
+func (s *PushJobsRepository) RetryDeadLetter(id int64) error {
+	return s.inTransaction(func(db models.DB) error {
+		result, err := db.Exec(`INSERT INTO `+models.PushJobTableName+` (
+                    target_user_id, type, message, user_id, user_phone, list_id, item_id, silent
+                    ) 
+		SELECT target_user_id, type, message, user_id, user_phone, list_id, item_id, silent 
+		FROM `+models.PushDeadLetterTableName+` 
+		WHERE id = ?`, id)
+		if err != nil {
+			return errors.New("Error retry push dead letter; " + err.Error())
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrNotFound{}
+		}
+
+		_, err = db.Exec(`DELETE FROM `+models.PushDeadLetterTableName+` WHERE id = ?`, id)
+		if err != nil {
+			return errors.New("Error delete push dead letter; " + err.Error())
+		}
+
+		return nil
+	})
+}
+
+// Выполнить запросы в транзакции. Если репозиторий создан на транзакции, запросы выполняются в ней
+func (s *PushJobsRepository) inTransaction(fn func(db models.DB) error) error {
+	db, ok := s.db.(*sql.DB)
+	if !ok {
+		return fn(s.db)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return errors.New("Error open transaction; " + err.Error())
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func deletePushJob(db models.DB, job *models.PushJob) error {
+	result, err := db.Exec(`DELETE FROM `+models.PushJobTableName+` WHERE id = ? AND lock_token = ?`,
+		job.ID, job.LockToken)
+
+	if err != nil {
+		return errors.New("Error delete push job; " + err.Error())
+	}
+
+	return checkPushJobLock(result)
+}
+
+func insertPushDeadLetter(db models.DB, job *models.PushJob) error {
+	_, err := db.Exec(`INSERT INTO `+models.PushDeadLetterTableName+` (
+                    id, target_user_id, type, message, user_id, user_phone, list_id, item_id, silent, tokens, 
+                    attempts, last_error, created_at
+                    ) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?))
+		ON DUPLICATE KEY UPDATE 
+			tokens = VALUES(tokens), 
+			attempts = VALUES(attempts), 
+			last_error = VALUES(last_error), 
+			failed_at = NOW()`,
+		job.ID, job.TargetUserId, job.TypeNotification, job.Message, job.UserId, job.UserPhone, job.ListId, job.ItemId,
+		job.Silent, job.Tokens, job.Attempts, truncateError(job.LastError), job.CreatedAt)
+
+	if err != nil {
+		return errors.New("Error insert push dead letter; " + err.Error())
+	}
+
+	return nil
+}
+
+func checkPushJobLock(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrPushJobLockLost
+	}
+
+	return nil
+}
+
+func scanPushJobs(rows *sql.Rows) ([]models.PushJob, error) {
+	defer rows.Close()
+
+	jobs := make([]models.PushJob, 0)
+
+	for rows.Next() {
+		var m models.PushJob
+		err := rows.Scan(&m.ID, &m.TargetUserId, &m.TypeNotification, &m.Message, &m.UserId, &m.UserPhone, &m.ListId,
+			&m.ItemId, &m.Silent, &m.Tokens, &m.Attempts, &m.LastError, &m.NextAttemptAt, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, m)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Ошибка сохраняется в колонку ограниченной длины
+func truncateError(message string) string {
+	runes := []rune(message)
+	if len(runes) > 500 {
+		return string(runes[:500])
+	}
+
+	return message
+}
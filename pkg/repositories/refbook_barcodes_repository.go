@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"shopingList/pkg/models"
+)
+
+// Штрихкоды товаров справочника. Штрихкод принадлежит только одному товару
+type RefbookBarcodesRepository struct {
+	db models.DB
+}
+
+func NewRefbookBarcodesRepository(db models.DB) RefbookBarcodesRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return RefbookBarcodesRepository{db: db}
+}
+
+// Вернуть штрихкоды по id товара
+func (s *RefbookBarcodesRepository) GetAll() (map[int64][]string, error) {
+	rows, err := s.db.Query(`SELECT product_id, barcode FROM ` + models.RefbookBarcodeTableName + ` ORDER BY product_id, barcode`)
+	if err != nil {
+		return nil, errors.New("Error get barcodes; " + err.Error())
+	}
+	defer rows.Close()
+
+	barcodes := make(map[int64][]string)
+
+	for rows.Next() {
+		var productId int64
+		var barcode string
+		if err := rows.Scan(&productId, &barcode); err != nil {
+			return nil, err
+		}
+
+		barcodes[productId] = append(barcodes[productId], barcode)
+	}
+
+	return barcodes, rows.Err()
+}
+
+// Вернуть id действующего товара по штрихкоду
+func (s *RefbookBarcodesRepository) GetProductId(barcode string) (int64, error) {
+	var productId int64
+
+	err := s.db.QueryRow(
+		`SELECT b.product_id 
+		FROM `+models.RefbookBarcodeTableName+` AS b
+		JOIN `+models.RefbookProductTableName+` AS p ON (p.id = b.product_id)
+		WHERE b.barcode = ? AND p.is_deleted = 0`,
+		barcode).Scan(&productId)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound{}
+		}
+
+		return 0, err
+	}
+
+	return productId, nil
+}
+
+// Заменить штрихкоды товара. Штрихкод, привязанный к другому товару, переносится на этот
+func (s *RefbookBarcodesRepository) Replace(productId int64, barcodes []string) error {
+	if _, err := s.db.Exec(`DELETE FROM `+models.RefbookBarcodeTableName+` WHERE product_id = ?`, productId); err != nil {
+		return errors.New("Error delete barcodes; " + err.Error())
+	}
+
+	for _, barcode := range barcodes {
+		_, err := s.db.Exec(`INSERT INTO `+models.RefbookBarcodeTableName+` (barcode, product_id) VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE product_id = VALUES(product_id)`,
+			barcode, productId)
+		if err != nil {
+			return errors.New("Error insert barcode; " + err.Error())
+		}
+	}
+
+	return nil
+}
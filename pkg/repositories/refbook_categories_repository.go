@@ -18,13 +18,57 @@ func NewRefbookCategoriesRepository(db models.DB) RefbookCategoriesRepository {
 	return RefbookCategoriesRepository{db: db}
 }
 
+// Вернуть действующие категории
 func (s *RefbookCategoriesRepository) GetAll() ([]models.RefbookCategory, error) {
+	return s.getList(`WHERE is_deleted = 0`)
+}
+
+// Вернуть все категории, включая удаленные
+func (s *RefbookCategoriesRepository) GetAllWithDeleted() ([]models.RefbookCategory, error) {
+	return s.getList(``)
+}
+
+// Вернуть категории, измененные или удаленные начиная с даты since
+func (s *RefbookCategoriesRepository) GetUpdatedSince(since int64) ([]models.RefbookCategory, error) {
+	return s.getList(`WHERE updated_at >= FROM_UNIXTIME(?)`, since)
+}
+
+// Вернуть действующую категорию по id
+func (s *RefbookCategoriesRepository) GetOneById(id int64) (models.RefbookCategory, error) {
+	categories, err := s.getList(`WHERE id = ? AND is_deleted = 0`, id)
+	if err != nil {
+		return models.RefbookCategory{}, err
+	}
+
+	if len(categories) == 0 {
+		return models.RefbookCategory{}, ErrNotFound{}
+	}
+
+	return categories[0], nil
+}
+
+func (s *RefbookCategoriesRepository) GetState() (models.RefbookTableState, error) {
+	var state models.RefbookTableState
+
+	err := s.db.QueryRow(
+		`SELECT IFNULL(UNIX_TIMESTAMP(MAX(updated_at)), 0), COUNT(*) FROM `+models.RefbookCategoryTableName).
+		Scan(&state.LastUpdatedAt, &state.Count)
+	if err != nil {
+		return state, errors.New("Error get state of categories; " + err.Error())
+	}
+
+	return state, nil
+}
+
+func (s *RefbookCategoriesRepository) getList(where string, args ...interface{}) ([]models.RefbookCategory, error) {
 	rows, err := s.db.Query(
 		`SELECT id,
        			title, 
+				is_deleted,
        			UNIX_TIMESTAMP(created_at), 
 				UNIX_TIMESTAMP(updated_at)
-		FROM ` + models.RefbookCategoryTableName)
+		FROM `+models.RefbookCategoryTableName+` `+where,
+		args...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -39,7 +83,7 @@ func (s *RefbookCategoriesRepository) GetAll() ([]models.RefbookCategory, error)
 
 	for rows.Next() {
 		var m models.RefbookCategory
-		err = rows.Scan(&m.ID, &m.Title, &m.CreatedAt, &m.UpdatedAt)
+		err = rows.Scan(&m.ID, &m.Title, &m.IsDeleted, &m.CreatedAt, &m.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -71,3 +115,31 @@ func (s *RefbookCategoriesRepository) Create(form *models.RefbookCategory) (int6
 
 	return result.LastInsertId()
 }
+
+// Обновить категорию. Восстанавливает удаленную категорию
+func (s *RefbookCategoriesRepository) Update(form *models.RefbookCategory) error {
+	_, err := s.db.Exec(`UPDATE `+models.RefbookCategoryTableName+` 
+		SET title = ?, is_deleted = 0, updated_at = NOW() 
+		WHERE id = ?`,
+		form.Title, form.ID)
+
+	if err != nil {
+		return errors.New("Error update category; " + err.Error())
+	}
+
+	return nil
+}
+
+// Пометить категорию удаленной. Id остается занятым, чтобы клиенты получили удаление
+func (s *RefbookCategoriesRepository) Delete(id int64) error {
+	_, err := s.db.Exec(`UPDATE `+models.RefbookCategoryTableName+` 
+		SET is_deleted = 1, updated_at = NOW() 
+		WHERE id = ? AND is_deleted = 0`,
+		id)
+
+	if err != nil {
+		return errors.New("Error delete category; " + err.Error())
+	}
+
+	return nil
+}
@@ -18,14 +18,59 @@ func NewRefbookProductsRepository(db models.DB) RefbookProductsRepository {
 	return RefbookProductsRepository{db: db}
 }
 
+// Вернуть действующие товары
 func (s *RefbookProductsRepository) GetAll() (*[]models.RefbookProduct, error) {
+	return s.getList(`WHERE is_deleted = 0`)
+}
+
+// Вернуть все товары, включая удаленные
+func (s *RefbookProductsRepository) GetAllWithDeleted() (*[]models.RefbookProduct, error) {
+	return s.getList(``)
+}
+
+// Вернуть товары, измененные или удаленные начиная с даты since
+func (s *RefbookProductsRepository) GetUpdatedSince(since int64) (*[]models.RefbookProduct, error) {
+	return s.getList(`WHERE updated_at >= FROM_UNIXTIME(?)`, since)
+}
+
+// Вернуть действующий товар по id
+func (s *RefbookProductsRepository) GetOneById(id int64) (models.RefbookProduct, error) {
+	products, err := s.getList(`WHERE id = ? AND is_deleted = 0`, id)
+	if err != nil {
+		return models.RefbookProduct{}, err
+	}
+
+	if products == nil || len(*products) == 0 {
+		return models.RefbookProduct{}, ErrNotFound{}
+	}
+
+	return (*products)[0], nil
+}
+
+func (s *RefbookProductsRepository) GetState() (models.RefbookTableState, error) {
+	var state models.RefbookTableState
+
+	err := s.db.QueryRow(
+		`SELECT IFNULL(UNIX_TIMESTAMP(MAX(updated_at)), 0), COUNT(*) FROM `+models.RefbookProductTableName).
+		Scan(&state.LastUpdatedAt, &state.Count)
+	if err != nil {
+		return state, errors.New("Error get state of products; " + err.Error())
+	}
+
+	return state, nil
+}
+
+func (s *RefbookProductsRepository) getList(where string, args ...interface{}) (*[]models.RefbookProduct, error) {
 	rows, err := s.db.Query(
 		`SELECT id,
        			title, 
 				category_id,
+				default_unit,
+				is_deleted,
        			UNIX_TIMESTAMP(created_at), 
 				UNIX_TIMESTAMP(updated_at)
-		FROM ` + models.RefbookProductTableName)
+		FROM `+models.RefbookProductTableName+` `+where,
+		args...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -40,7 +85,7 @@ func (s *RefbookProductsRepository) GetAll() (*[]models.RefbookProduct, error) {
 
 	for rows.Next() {
 		var m models.RefbookProduct
-		err = rows.Scan(&m.ID, &m.Title, &m.CategoryId, &m.CreatedAt, &m.UpdatedAt)
+		err = rows.Scan(&m.ID, &m.Title, &m.CategoryId, &m.DefaultUnit, &m.IsDeleted, &m.CreatedAt, &m.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -62,9 +107,9 @@ func (s *RefbookProductsRepository) Clear() error {
 
 func (s *RefbookProductsRepository) Create(form *models.RefbookProduct) (int64, error) {
 	result, err := s.db.Exec(`INSERT INTO `+models.RefbookProductTableName+` (
-                    title, category_id) 
-		VALUES (?, ?)`,
-		form.Title, form.CategoryId)
+                    title, category_id, default_unit) 
+		VALUES (?, ?, ?)`,
+		form.Title, form.CategoryId, form.DefaultUnit)
 
 	if err != nil {
 		return 0, errors.New("Error insert product; " + err.Error())
@@ -72,3 +117,31 @@ func (s *RefbookProductsRepository) Create(form *models.RefbookProduct) (int64,
 
 	return result.LastInsertId()
 }
+
+// Обновить товар. Восстанавливает удаленный товар
+func (s *RefbookProductsRepository) Update(form *models.RefbookProduct) error {
+	_, err := s.db.Exec(`UPDATE `+models.RefbookProductTableName+` 
+		SET title = ?, category_id = ?, default_unit = ?, is_deleted = 0, updated_at = NOW() 
+		WHERE id = ?`,
+		form.Title, form.CategoryId, form.DefaultUnit, form.ID)
+
+	if err != nil {
+		return errors.New("Error update product; " + err.Error())
+	}
+
+	return nil
+}
+
+// Пометить товар удаленным. Id остается занятым, т.к. на него ссылаются товары пользователей и списков
+func (s *RefbookProductsRepository) Delete(id int64) error {
+	_, err := s.db.Exec(`UPDATE `+models.RefbookProductTableName+` 
+		SET is_deleted = 1, updated_at = NOW() 
+		WHERE id = ? AND is_deleted = 0`,
+		id)
+
+	if err != nil {
+		return errors.New("Error delete product; " + err.Error())
+	}
+
+	return nil
+}
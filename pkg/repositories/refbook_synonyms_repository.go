@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+// Синонимы товаров справочника
+type RefbookSynonymsRepository struct {
+	db models.DB
+}
+
+func NewRefbookSynonymsRepository(db models.DB) RefbookSynonymsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return RefbookSynonymsRepository{db: db}
+}
+
+// Вернуть синонимы по id товара
+func (s *RefbookSynonymsRepository) GetAll() (map[int64][]string, error) {
+	rows, err := s.db.Query(`SELECT product_id, title FROM ` + models.RefbookSynonymTableName + ` ORDER BY product_id, title`)
+	if err != nil {
+		return nil, errors.New("Error get synonyms; " + err.Error())
+	}
+	defer rows.Close()
+
+	synonyms := make(map[int64][]string)
+
+	for rows.Next() {
+		var productId int64
+		var title string
+		if err := rows.Scan(&productId, &title); err != nil {
+			return nil, err
+		}
+
+		synonyms[productId] = append(synonyms[productId], title)
+	}
+
+	return synonyms, rows.Err()
+}
+
+// Заменить синонимы товара
+func (s *RefbookSynonymsRepository) Replace(productId int64, synonyms []string) error {
+	if _, err := s.db.Exec(`DELETE FROM `+models.RefbookSynonymTableName+` WHERE product_id = ?`, productId); err != nil {
+		return errors.New("Error delete synonyms; " + err.Error())
+	}
+
+	for _, synonym := range synonyms {
+		_, err := s.db.Exec(`INSERT IGNORE INTO `+models.RefbookSynonymTableName+` (product_id, title) VALUES (?, ?)`,
+			productId, synonym)
+		if err != nil {
+			return errors.New("Error insert synonym; " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+// Переводы названий категорий или товаров справочника.
+// Основное название хранится в самой записи на языке по умолчанию
+type RefbookTranslationsRepository struct {
+	db       models.DB
+	table    string
+	idColumn string
+}
+
+func NewRefbookCategoryTranslationsRepository(db models.DB) RefbookTranslationsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return RefbookTranslationsRepository{db: db, table: models.RefbookCategoryTranslationTableName, idColumn: "category_id"}
+}
+
+func NewRefbookProductTranslationsRepository(db models.DB) RefbookTranslationsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return RefbookTranslationsRepository{db: db, table: models.RefbookProductTranslationTableName, idColumn: "product_id"}
+}
+
+// Вернуть названия на языке locale по id записи
+func (s *RefbookTranslationsRepository) GetForLocale(locale string) (map[int64]string, error) {
+	rows, err := s.db.Query(`SELECT `+s.idColumn+`, title FROM `+s.table+` WHERE locale = ?`, locale)
+	if err != nil {
+		return nil, errors.New("Error get translations; " + err.Error())
+	}
+	defer rows.Close()
+
+	titles := make(map[int64]string)
+
+	for rows.Next() {
+		var id int64
+		var title string
+		if err := rows.Scan(&id, &title); err != nil {
+			return nil, err
+		}
+
+		titles[id] = title
+	}
+
+	return titles, rows.Err()
+}
+
+// Вернуть все переводы: id записи -> язык -> название
+func (s *RefbookTranslationsRepository) GetAll() (map[int64]map[string]string, error) {
+	rows, err := s.db.Query(`SELECT ` + s.idColumn + `, locale, title FROM ` + s.table)
+	if err != nil {
+		return nil, errors.New("Error get translations; " + err.Error())
+	}
+	defer rows.Close()
+
+	titles := make(map[int64]map[string]string)
+
+	for rows.Next() {
+		var id int64
+		var locale, title string
+		if err := rows.Scan(&id, &locale, &title); err != nil {
+			return nil, err
+		}
+
+		if titles[id] == nil {
+			titles[id] = make(map[string]string)
+		}
+
+		titles[id][locale] = title
+	}
+
+	return titles, rows.Err()
+}
+
+// Заменить переводы записи
+func (s *RefbookTranslationsRepository) Replace(id int64, titles map[string]string) error {
+	if _, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE `+s.idColumn+` = ?`, id); err != nil {
+		return errors.New("Error delete translations; " + err.Error())
+	}
+
+	for locale, title := range titles {
+		_, err := s.db.Exec(`INSERT INTO `+s.table+` (`+s.idColumn+`, locale, title) VALUES (?, ?, ?)`,
+			id, locale, title)
+		if err != nil {
+			return errors.New("Error insert translation; " + err.Error())
+		}
+	}
+
+	return nil
+}
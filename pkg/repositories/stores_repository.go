@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type StoresRepository struct {
+	db models.DB
+}
+
+func NewStoresRepository(db models.DB) StoresRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return StoresRepository{db: db}
+}
+
+func (s *StoresRepository) GetOneById(id string) (models.Store, error) {
+	row := s.db.QueryRow(
+		`SELECT id,
+       			owner_id,
+       			name,
+       			category_order,
+       			UNIX_TIMESTAMP(created_at), 
+       			UNIX_TIMESTAMP(updated_at), 
+       			UNIX_TIMESTAMP(received_at),
+       			is_deleted 
+		FROM `+models.StoreTableName+`
+		WHERE id = ?`, id)
+
+	var i models.Store
+	err := row.Scan(&i.ID, &i.OwnerID, &i.Name, &i.CategoryOrder, &i.CreatedAt, &i.UpdatedAt, &i.ReceivedAt, &i.IsDeleted)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Store{}, ErrNotFound{}
+		}
+
+		return models.Store{}, err
+	}
+
+	return i, nil
+}
+
+func (s *StoresRepository) Create(store *models.Store) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.StoreTableName+` (
+                    id, owner_id, name, category_order, is_deleted, created_at, updated_at, received_at
+                    ) 
+		VALUES (?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?), FROM_UNIXTIME(?))`,
+		store.ID, store.OwnerID, store.Name, store.CategoryOrder.SqlValue(), store.IsDeleted,
+		store.CreatedAt, store.UpdatedAt, store.ReceivedAt)
+
+	if err != nil {
+		return errors.New("Error insert store; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *StoresRepository) Update(store *models.Store) error {
+	_, err := s.db.Exec(`UPDATE `+models.StoreTableName+`
+		SET name=?, category_order=?, is_deleted=?, updated_at=FROM_UNIXTIME(?), received_at=FROM_UNIXTIME(?)
+		WHERE id=?`,
+		store.Name, store.CategoryOrder.SqlValue(), store.IsDeleted, store.UpdatedAt, store.ReceivedAt, store.ID)
+
+	if err != nil {
+		return errors.New("Error update store; " + err.Error())
+	}
+
+	return nil
+}
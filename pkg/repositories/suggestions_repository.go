@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type SuggestionsRepository struct {
+	db models.DB
+}
+
+func NewSuggestionsRepository(db models.DB) SuggestionsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return SuggestionsRepository{db: db}
+}
+
+// Заменить статистику покупок пользователя. Вызывать в транзакции
+func (s *SuggestionsRepository) ReplaceForUser(userId string, stats []models.ProductStat, pairs []models.ProductPair) error {
+	if _, err := s.db.Exec(`DELETE FROM `+models.SuggestionProductsTableName+` WHERE user_id = ?`, userId); err != nil {
+		return errors.New("Error delete suggestion products; " + err.Error())
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM `+models.SuggestionPairsTableName+` WHERE user_id = ?`, userId); err != nil {
+		return errors.New("Error delete suggestion pairs; " + err.Error())
+	}
+
+	for _, stat := range stats {
+		_, err := s.db.Exec(`INSERT INTO `+models.SuggestionProductsTableName+` (
+                    user_id, product_key, name, product_id, category_id, purchases_count, interval_days, last_bought_at
+                    ) 
+			VALUES (?, ?, ?, ?, ?, ?, ?, FROM_UNIXTIME(?))`,
+			userId, stat.ProductKey, stat.Name, stat.ProductID.SqlValue(), stat.CategoryID.SqlValue(),
+			stat.PurchasesCount, stat.IntervalDays, stat.LastBoughtAt)
+
+		if err != nil {
+			return errors.New("Error insert suggestion product; " + err.Error())
+		}
+	}
+
+	for _, pair := range pairs {
+		_, err := s.db.Exec(`INSERT INTO `+models.SuggestionPairsTableName+` (
+                    user_id, product_key, related_key, related_name, support, confidence
+                    ) 
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			userId, pair.ProductKey, pair.RelatedKey, pair.RelatedName, pair.Support, pair.Confidence)
+
+		if err != nil {
+			return errors.New("Error insert suggestion pair; " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type TripsRepository struct {
+	db models.DB
+}
+
+func NewTripsRepository(db models.DB) TripsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return TripsRepository{db: db}
+}
+
+// Создать поход. Возвращает ErrDuplicate, если по списку уже есть активный поход
+func (s *TripsRepository) Create(trip *models.Trip) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.TripTableName+` (
+                    id, list_id, user_id, store_id, status, started_at, finished_at
+                    ) 
+		VALUES (?, ?, ?, ?, ?, FROM_UNIXTIME(?), FROM_UNIXTIME(?))`,
+		trip.ID, trip.ListID, trip.UserID, trip.StoreID.SqlValue(), trip.Status,
+		trip.StartedAt, nullableTimestamp(trip.FinishedAt))
+
+	if isDuplicateKeyError(err) {
+		return ErrDuplicate{}
+	}
+
+	if err != nil {
+		return errors.New("Error insert trip; " + err.Error())
+	}
+
+	return nil
+}
+
+// Завершить или отменить активный поход. Возвращает false, если поход уже не активен
+func (s *TripsRepository) Close(trip *models.Trip) (bool, error) {
+	result, err := s.db.Exec(`UPDATE `+models.TripTableName+`
+		SET status=?, finished_at=FROM_UNIXTIME(?)
+		WHERE id=? AND status=?`,
+		trip.Status, trip.FinishedAt, trip.ID, models.TripStatusActive)
+
+	if err != nil {
+		return false, errors.New("Error update trip; " + err.Error())
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
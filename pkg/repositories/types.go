@@ -1,8 +1,27 @@
 package repositories
 
+import "github.com/go-sql-driver/mysql"
+
+// Код ошибки MySQL ER_DUP_ENTRY
+const mysqlErrDuplicateEntry = 1062
+
 type ErrNotFound struct {
 }
 
 func (s ErrNotFound) Error() string {
 	return "object not found in repository"
 }
+
+// Нарушение уникального ключа при вставке
+type ErrDuplicate struct {
+}
+
+func (s ErrDuplicate) Error() string {
+	return "object already exists in repository"
+}
+
+func isDuplicateKeyError(err error) bool {
+	mysqlErr, ok := err.(*mysql.MySQLError)
+
+	return ok && mysqlErr.Number == mysqlErrDuplicateEntry
+}
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+)
+
+type WebPushSubscriptionsRepository struct {
+	db models.DB
+}
+
+func NewWebPushSubscriptionsRepository(db models.DB) WebPushSubscriptionsRepository {
+	if db == nil {
+		panic("db param is nil")
+	}
+
+	return WebPushSubscriptionsRepository{db: db}
+}
+
+// Сохранить подписку. Если браузер уже подписан, подписка переходит к пользователю, вошедшему последним
+func (s *WebPushSubscriptionsRepository) Save(userId string, subscription *models.WebPushSubscription) error {
+	_, err := s.db.Exec(`INSERT INTO `+models.WebPushSubscriptionTableName+` (
+                    user_id, endpoint, endpoint_hash, p256dh, auth) 
+		VALUES (?, ?, SHA2(?, 256), ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			user_id = VALUES(user_id), 
+			p256dh = VALUES(p256dh), 
+			auth = VALUES(auth)`,
+		userId, subscription.Endpoint, subscription.Endpoint, subscription.Keys.P256dh, subscription.Keys.Auth)
+
+	if err != nil {
+		return errors.New("Error save web push subscription; " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *WebPushSubscriptionsRepository) Delete(userId string, endpoint string) error {
+	result, err := s.db.Exec(`DELETE FROM `+models.WebPushSubscriptionTableName+` 
+		WHERE user_id = ? AND endpoint_hash = SHA2(?, 256)`,
+		userId, endpoint)
+	if err != nil {
+		return errors.New("Error delete web push subscription; " + err.Error())
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound{}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package scheduler
+
+import "time"
+
+// Источник текущего времени для фоновых задач. Подменяется в тестах
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct {
+}
+
+func (s SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
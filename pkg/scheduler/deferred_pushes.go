@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services"
+	"time"
+)
+
+// Сколько отложенных push отправляется за один проход
+const deferredPushesBatch = 100
+
+// Отправка push, отложенных до конца тихих часов получателей
+type DeferredPushesReleaser struct {
+	repository     repositories.DeferredPushesRepository
+	readRepository readModels.DeferredPushesReadRepository
+	clock          Clock
+	interval       time.Duration
+
+	// Общий канал push. Отложенные push снова проходят проверку тихих часов, если получатель их изменил
+	PushChannel chan services.PushNotificationMessage
+}
+
+func NewDeferredPushesReleaser(
+	repository repositories.DeferredPushesRepository,
+	readRepository readModels.DeferredPushesReadRepository,
+	clock Clock,
+	interval time.Duration) *DeferredPushesReleaser {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &DeferredPushesReleaser{
+		repository:     repository,
+		readRepository: readRepository,
+		clock:          clock,
+		interval:       interval}
+}
+
+// Отправить push сразу и затем по таймеру до закрытия канала stop
+func (s *DeferredPushesReleaser) Run(stop chan struct{}) {
+	if err := s.Release(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in DeferredPushesReleaser"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Release(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in DeferredPushesReleaser"))
+			}
+		}
+	}
+}
+
+// Отправить все push, у которых закончились тихие часы
+func (s *DeferredPushesReleaser) Release() error {
+	if s.PushChannel == nil {
+		return errors.New("PushChannel is nil")
+	}
+
+	now := s.clock.Now().Unix()
+
+	for {
+		pushes, err := s.readRepository.GetDue(now, deferredPushesBatch)
+		if err != nil {
+			return errors.Wrap(err, "Error get deferred pushes")
+		}
+
+		if len(pushes) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(pushes))
+		for _, push := range pushes {
+			s.PushChannel <- services.PushNotificationMessage{Notification: push, TargetUserIds: []string{push.TargetUserId}}
+			ids = append(ids, push.ID)
+		}
+
+		if err := s.repository.Delete(ids); err != nil {
+			return err
+		}
+
+		if len(pushes) < deferredPushesBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/repositories"
+	"time"
+)
+
+// Сколько уведомлений удаляется за один запрос
+const notificationsCleanBatch = 1000
+
+// Удаление уведомлений старше срока хранения
+type NotificationsCleaner struct {
+	repository repositories.NotificationsRepository
+	retention  time.Duration
+	clock      Clock
+	interval   time.Duration
+}
+
+func NewNotificationsCleaner(
+	repository repositories.NotificationsRepository,
+	retention time.Duration,
+	clock Clock,
+	interval time.Duration) *NotificationsCleaner {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &NotificationsCleaner{
+		repository: repository,
+		retention:  retention,
+		clock:      clock,
+		interval:   interval}
+}
+
+// Удалить старые уведомления сразу и затем по таймеру до закрытия канала stop
+func (s *NotificationsCleaner) Run(stop chan struct{}) {
+	if _, err := s.Clean(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in NotificationsCleaner"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := s.Clean(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in NotificationsCleaner"))
+			}
+		}
+	}
+}
+
+// Удалить уведомления старше срока хранения, вернуть количество удаленных
+func (s *NotificationsCleaner) Clean() (int64, error) {
+	before := s.clock.Now().Add(-s.retention).Unix()
+
+	var total int64
+	for {
+		deleted, err := s.repository.DeleteOlderThan(before, notificationsCleanBatch)
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+
+		if deleted < notificationsCleanBatch {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/models"
+	"shopingList/pkg/notifications"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/services"
+	"shopingList/store"
+	"time"
+)
+
+const (
+	DefaultCoalesceWindow   = 2 * time.Minute  // Сколько ждать новых событий после последнего
+	DefaultCoalesceMaxDelay = 10 * time.Minute // Сколько максимально откладывать первое событие
+	DefaultDigestHour       = 20               // Час отправки ежедневного дайджеста, UTC
+)
+
+// Создание уведомлений из отложенных событий по товарам.
+// События хранятся в базе, поэтому после перезапуска не теряются и отправляются при первом запуске
+type NotificationsFlusher struct {
+	dataService    store.DataService
+	readRepository readModels.PendingNotificationsReadRepository
+	clock          Clock
+	interval       time.Duration
+	Window         time.Duration
+	MaxDelay       time.Duration
+	DigestHour     int
+	PushChannel    chan services.PushNotificationMessage
+	Renderer       *notifications.Renderer
+	Emails         *notifications.EmailNotifier
+}
+
+func NewNotificationsFlusher(
+	dataService store.DataService,
+	readRepository readModels.PendingNotificationsReadRepository,
+	clock Clock,
+	interval time.Duration) *NotificationsFlusher {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &NotificationsFlusher{
+		dataService:    dataService,
+		readRepository: readRepository,
+		clock:          clock,
+		interval:       interval,
+		Window:         DefaultCoalesceWindow,
+		MaxDelay:       DefaultCoalesceMaxDelay,
+		DigestHour:     DefaultDigestHour}
+}
+
+// Отправить накопленные события сразу и затем по таймеру до закрытия канала stop
+func (s *NotificationsFlusher) Run(stop chan struct{}) {
+	if err := s.Flush(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in NotificationsFlusher"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in NotificationsFlusher"))
+			}
+		}
+	}
+}
+
+// Создать уведомления для групп, которые пора отправить, и для дайджестов, время которых наступило
+func (s *NotificationsFlusher) Flush() error {
+	now := s.clock.Now()
+
+	events, err := s.readRepository.GetDueGroups(now.Add(-s.Window).Unix(), now.Add(-s.MaxDelay).Unix())
+	if err != nil {
+		return errors.Wrap(err, "Error get pending notifications")
+	}
+
+	for _, group := range notifications.GroupPending(events) {
+		if err := s.send(group.Form(), group.IDs(), group.Push()); err != nil {
+			log.Errorln(errors.Wrapf(err, "Error flush notifications for user %s", group.TargetUserId))
+		}
+	}
+
+	events, err = s.readRepository.GetDigestBefore(s.lastDigestTime(now).Unix())
+	if err != nil {
+		return errors.Wrap(err, "Error get pending digest")
+	}
+
+	for _, group := range notifications.GroupDigest(events) {
+		if err := s.send(group.DigestForm(), group.IDs(), true); err != nil {
+			log.Errorln(errors.Wrapf(err, "Error flush digest for user %s", group.TargetUserId))
+		}
+	}
+
+	return nil
+}
+
+// Уведомление создается и события удаляются в одной транзакции, push и письмо отправляются после коммита
+func (s *NotificationsFlusher) send(form models.NotificationCreateForm, ids []int64, push bool) error {
+	form.Message = s.Renderer.Messages(form, []string{form.TargetUserId})[form.TargetUserId]
+
+	tx, err := s.dataService.CreateTransaction()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	notificationsRepository := s.dataService.GetNotificationsRepository(tx)
+	if err = notificationsRepository.Create(&form); err != nil {
+		return err
+	}
+
+	pendingRepository := s.dataService.GetPendingNotificationsRepository(tx)
+	if err = pendingRepository.Delete(ids); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	s.Emails.Notify(form.TypeNotification, []string{form.TargetUserId}, map[string]string{form.TargetUserId: form.Message})
+
+	if push && s.PushChannel != nil {
+		s.PushChannel <- services.PushNotificationMessage{Notification: form, TargetUserIds: []string{form.TargetUserId}}
+	}
+
+	return nil
+}
+
+// Последний наступивший момент отправки дайджеста
+func (s *NotificationsFlusher) lastDigestTime(now time.Time) time.Time {
+	digest := time.Date(now.Year(), now.Month(), now.Day(), s.DigestHour, 0, 0, 0, time.UTC)
+	if digest.After(now) {
+		digest = digest.AddDate(0, 0, -1)
+	}
+
+	return digest
+}
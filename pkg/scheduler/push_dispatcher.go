@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"math/rand"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services/push"
+	"sync"
+	"time"
+)
+
+// Сколько заданий захватывается за один запрос
+const pushJobsBatch = 100
+
+// Время захвата задания. Если обработчик не уложился, задание достанется другому
+const pushJobLease = 2 * time.Minute
+
+// Отправка push из очереди с повторами и dead letter
+type PushDispatcher struct {
+	repository repositories.PushJobsRepository
+	tokens     push.TokenStorage
+	provider   push.Provider
+	config     models.PushQueueConfig
+	clock      Clock
+	interval   time.Duration
+}
+
+func NewPushDispatcher(
+	repository repositories.PushJobsRepository,
+	tokens push.TokenStorage,
+	provider push.Provider,
+	config models.PushQueueConfig,
+	clock Clock,
+	interval time.Duration) *PushDispatcher {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &PushDispatcher{
+		repository: repository,
+		tokens:     tokens,
+		provider:   provider,
+		config:     config.WithDefaults(),
+		clock:      clock,
+		interval:   interval}
+}
+
+// Обработать очередь сразу и затем по таймеру до закрытия канала stop
+func (s *PushDispatcher) Run(stop chan struct{}) {
+	if err := s.Dispatch(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in PushDispatcher"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Dispatch(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in PushDispatcher"))
+			}
+		}
+	}
+}
+
+// Отправить все задания, у которых наступило время попытки
+func (s *PushDispatcher) Dispatch() error {
+	for {
+		jobs, err := s.repository.Claim(s.clock.Now().Unix(), pushJobsBatch, int64(pushJobLease/time.Second))
+		if err != nil {
+			return err
+		}
+
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		s.processAll(jobs)
+
+		if len(jobs) < pushJobsBatch {
+			return nil
+		}
+	}
+}
+
+func (s *PushDispatcher) processAll(jobs []models.PushJob) {
+	queue := make(chan models.PushJob)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < s.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := s.process(job); err != nil {
+					log.Errorln(errors.Wrap(err, "Error process push job"))
+				}
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+
+	wg.Wait()
+}
+
+func (s *PushDispatcher) process(job models.PushJob) error {
+	tokens := []models.FCMToken(job.Tokens)
+
+	if job.Tokens == nil {
+		var err error
+		tokens, err = s.tokens.GetTokensForUsers([]string{job.TargetUserId})
+		if err != nil {
+			return s.retry(job, nil, err.Error())
+		}
+	}
+
+	if len(tokens) == 0 {
+		return s.repository.Delete(&job)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushJobLease/2)
+	defer cancel()
+
+	results, err := s.provider.Send(ctx, tokens, push.NewMessage(job.PushPayload))
+	if err != nil {
+		return s.retry(job, tokens, err.Error())
+	}
+
+	retryTokens := make([]models.FCMToken, 0)
+	lastError := ""
+
+	for _, result := range results {
+		switch result.Status {
+		case push.StatusInvalidToken:
+			if err := s.tokens.Delete(job.TargetUserId, result.Token); err != nil {
+				log.Errorln(errors.Wrap(err, "Error delete invalid push token"))
+			}
+		case push.StatusRetry:
+			retryTokens = append(retryTokens, result.Token)
+			lastError = result.Error
+		case push.StatusFailed:
+			log.Warnf("Push job %d failed for token: %s", job.ID, result.Error)
+		}
+	}
+
+	if len(retryTokens) == 0 {
+		return s.repository.Delete(&job)
+	}
+
+	return s.retry(job, retryTokens, lastError)
+}
+
+// Запланировать повтор только для токенов с временной ошибкой.
+// Без токенов при повторе берутся текущие токены пользователя
+func (s *PushDispatcher) retry(job models.PushJob, tokens []models.FCMToken, lastError string) error {
+	job.Attempts++
+	job.Tokens = tokens
+	job.LastError = lastError
+
+	if job.Attempts >= s.config.MaxAttempts {
+		log.Warnf("Push job %d moved to dead letter after %d attempts: %s", job.ID, job.Attempts, lastError)
+		return s.repository.MoveToDeadLetter(&job)
+	}
+
+	job.NextAttemptAt = s.clock.Now().Add(s.backoff(job.Attempts)).Unix()
+
+	return s.repository.Reschedule(&job)
+}
+
+// Экспоненциальная задержка со случайным разбросом, чтобы повторы не приходили разом
+func (s *PushDispatcher) backoff(attempt int) time.Duration {
+	delay := time.Duration(s.config.BaseBackoffSeconds) * time.Second
+	maxDelay := time.Duration(s.config.MaxBackoffSeconds) * time.Second
+
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
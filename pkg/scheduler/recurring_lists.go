@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/events"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/sync"
+	"shopingList/store"
+	"time"
+	"unicode/utf8"
+)
+
+const maxListNameLength = 100
+
+// Планировщик создания списков из шаблонов по правилам повторения
+type RecurringListsScheduler struct {
+	dataService     store.DataService
+	repository      repositories.ListRecurrencesRepository
+	readRepository  readModels.ListRecurrencesReadRepository
+	clock           Clock
+	interval        time.Duration
+	ChanShareChange chan events.ShareListEvent
+
+	// Часовой пояс владельца для часа запуска. Если не задан, час считается по UTC
+	Settings *readModels.NotificationPreferencesReadRepository
+}
+
+func NewRecurringListsScheduler(
+	dataService store.DataService,
+	repository repositories.ListRecurrencesRepository,
+	readRepository readModels.ListRecurrencesReadRepository,
+	clock Clock,
+	interval time.Duration) *RecurringListsScheduler {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &RecurringListsScheduler{
+		dataService:    dataService,
+		repository:     repository,
+		readRepository: readRepository,
+		clock:          clock,
+		interval:       interval}
+}
+
+// Запускать обработку по таймеру до закрытия канала stop
+func (s *RecurringListsScheduler) Run(stop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.RunDue(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in RecurringListsScheduler"))
+			}
+		}
+	}
+}
+
+// Создать списки для всех правил, время которых наступило
+func (s *RecurringListsScheduler) RunDue() error {
+	now := s.clock.Now()
+
+	recurrences, err := s.readRepository.GetDue(now.Unix())
+	if err != nil {
+		return errors.Wrap(err, "Error get due recurrences")
+	}
+
+	for _, recurrence := range recurrences {
+		if err := s.materialize(&recurrence, now); err != nil {
+			log.Errorln(errors.Wrapf(err, "Error create list from recurrence of template %s", recurrence.ListID))
+		}
+	}
+
+	return nil
+}
+
+// Создать список из шаблона.
+// Время следующего запуска сдвигается до создания списка, чтобы постоянная ошибка
+// не приводила к созданию нового списка на каждом тике
+func (s *RecurringListsScheduler) materialize(recurrence *models.ListRecurrence, now time.Time) error {
+	usersReadRepository := s.dataService.GetUsersReadRepository()
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	owner, err := usersReadRepository.GetUser(recurrence.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if owner.ID == "" || owner.IsDeleted {
+		return s.disable(recurrence, now)
+	}
+
+	template, err := listsReadRepository.GetListForIdAndOwner(recurrence.ListID, recurrence.OwnerID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			return s.disable(recurrence, now)
+		}
+
+		return err
+	}
+
+	if template.IsDeleted || !template.IsTemplate {
+		return s.disable(recurrence, now)
+	}
+
+	location := time.UTC
+	if s.Settings != nil {
+		if location, err = s.Settings.GetLocationForUser(owner.ID); err != nil {
+			return err
+		}
+	}
+
+	recurrence.LastRunAt = now.Unix()
+	recurrence.NextRunAt = recurrence.NextRunAfter(now, location).Unix()
+	recurrence.UpdatedAt = now.Unix()
+
+	if err = s.repository.Save(recurrence); err != nil {
+		return err
+	}
+
+	cloner := sync.NewTemplateCloner(s.dataService, *owner)
+	pack, err := cloner.Clone(template.ID, sync.TemplateCloneOptions{
+		Name:             recurringListName(template.Name, now.In(location)),
+		ShareWithMembers: true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	s.sendEvents(pack.Lists[0], *owner, pack.Shares)
+
+	return nil
+}
+
+// Отключить правило, если шаблон или его владелец больше не существуют
+func (s *RecurringListsScheduler) disable(recurrence *models.ListRecurrence, now time.Time) error {
+	recurrence.IsDeleted = true
+	recurrence.UpdatedAt = now.Unix()
+
+	return s.repository.Save(recurrence)
+}
+
+func (s *RecurringListsScheduler) sendEvents(list models.List, owner models.User, shares []models.ListShare) {
+	if s.ChanShareChange == nil {
+		log.Warn("ChanShareChange in RecurringListsScheduler is nil")
+		return
+	}
+
+	s.ChanShareChange <- events.NewShareListEvent(events.ShareListEventRecurring, list, owner, owner.ID)
+
+	for _, share := range shares {
+		s.ChanShareChange <- events.NewShareListEvent(events.ShareListEventRecurring, list, owner, share.ToUserID)
+	}
+}
+
+func recurringListName(name string, now time.Time) string {
+	suffix := " " + now.Format("02.01")
+
+	for utf8.RuneCountInString(name)+len(suffix) > maxListNameLength {
+		runes := []rune(name)
+		name = string(runes[:len(runes)-1])
+	}
+
+	return name + suffix
+}
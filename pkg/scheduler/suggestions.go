@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/suggestions"
+	"shopingList/store"
+	"time"
+)
+
+// За какой период учитываются покупки
+const suggestionsHistoryDays = 180
+
+// Пакетный пересчет статистики покупок для подсказок
+type SuggestionsBuilder struct {
+	dataService             store.DataService
+	purchasesReadRepository readModels.PurchasesReadRepository
+	clock                   Clock
+	interval                time.Duration
+}
+
+func NewSuggestionsBuilder(
+	dataService store.DataService,
+	purchasesReadRepository readModels.PurchasesReadRepository,
+	clock Clock,
+	interval time.Duration) *SuggestionsBuilder {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &SuggestionsBuilder{
+		dataService:             dataService,
+		purchasesReadRepository: purchasesReadRepository,
+		clock:                   clock,
+		interval:                interval}
+}
+
+// Пересчитать статистику сразу и затем по таймеру до закрытия канала stop
+func (s *SuggestionsBuilder) Run(stop chan struct{}) {
+	if err := s.RunAll(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in SuggestionsBuilder"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.RunAll(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in SuggestionsBuilder"))
+			}
+		}
+	}
+}
+
+// Пересчитать статистику для всех пользователей с покупками за период
+func (s *SuggestionsBuilder) RunAll() error {
+	now := s.clock.Now()
+	from := now.AddDate(0, 0, -suggestionsHistoryDays).Unix()
+
+	userIds, err := s.purchasesReadRepository.GetUserIdsWithPurchasesSince(from)
+	if err != nil {
+		return errors.Wrap(err, "Error get users with purchases")
+	}
+
+	for _, userId := range userIds {
+		if err := s.buildForUser(userId, from, now.Unix()); err != nil {
+			log.Errorln(errors.Wrapf(err, "Error build suggestions for user %s", userId))
+		}
+	}
+
+	return nil
+}
+
+func (s *SuggestionsBuilder) buildForUser(userId string, from int64, to int64) error {
+	purchases, err := s.purchasesReadRepository.GetForUser(userId, "", from, to+1)
+	if err != nil {
+		return errors.Wrap(err, "Error get purchases")
+	}
+
+	stats, pairs := suggestions.BuildStats(userId, purchases)
+
+	tx, err := s.dataService.CreateTransaction()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	suggestionsRepository := s.dataService.GetSuggestionsRepository(tx)
+	if err = suggestionsRepository.ReplaceForUser(userId, stats, pairs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
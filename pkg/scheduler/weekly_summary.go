@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"shopingList/pkg/notifications"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/services/email"
+	"time"
+)
+
+const (
+	DefaultWeeklySummaryDay  = time.Monday // День отправки сводки
+	DefaultWeeklySummaryHour = 9           // Час отправки сводки, UTC
+)
+
+// Сколько получателей сводки обрабатывается за один запрос
+const weeklySummaryBatch = 100
+
+// Сколько раз повторяется неудачная отправка сводки за неделю
+const weeklySummaryMaxFailures = 3
+
+// Еженедельная сводка активности в списках на email.
+// Время отправки сохраняется у получателя, поэтому после перезапуска сводка не дублируется
+type WeeklySummarySender struct {
+	repository         repositories.NotificationPreferencesRepository
+	readRepository     readModels.NotificationPreferencesReadRepository
+	activityRepository readModels.ListActivityReadRepository
+	mailer             email.Mailer
+	clock              Clock
+	interval           time.Duration
+	Day                time.Weekday
+	Hour               int
+}
+
+func NewWeeklySummarySender(
+	repository repositories.NotificationPreferencesRepository,
+	readRepository readModels.NotificationPreferencesReadRepository,
+	activityRepository readModels.ListActivityReadRepository,
+	mailer email.Mailer,
+	clock Clock,
+	interval time.Duration) *WeeklySummarySender {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &WeeklySummarySender{
+		repository:         repository,
+		readRepository:     readRepository,
+		activityRepository: activityRepository,
+		mailer:             mailer,
+		clock:              clock,
+		interval:           interval,
+		Day:                DefaultWeeklySummaryDay,
+		Hour:               DefaultWeeklySummaryHour}
+}
+
+// Отправить сводки сразу и затем по таймеру до закрытия канала stop
+func (s *WeeklySummarySender) Run(stop chan struct{}) {
+	if err := s.Send(); err != nil {
+		log.Errorln(errors.Wrap(err, "Error in WeeklySummarySender"))
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.Send(); err != nil {
+				log.Errorln(errors.Wrap(err, "Error in WeeklySummarySender"))
+			}
+		}
+	}
+}
+
+// Отправить сводку за прошедшую неделю всем, кому она еще не отправлена
+func (s *WeeklySummarySender) Send() error {
+	now := s.clock.Now()
+	until := s.lastSummaryTime(now)
+	since := until.AddDate(0, 0, -7)
+
+	// Получатели перебираются по id, поэтому неудачные отправки не мешают следующим получателям
+	lastUserId := ""
+
+	for {
+		recipients, err := s.readRepository.GetWeeklyEmailRecipients(until.Unix(), lastUserId, weeklySummaryBatch)
+		if err != nil {
+			return errors.Wrap(err, "Error get weekly summary recipients")
+		}
+
+		for _, recipient := range recipients {
+			lastUserId = recipient.UserId
+
+			activity, err := s.activityRepository.GetForUser(recipient.UserId, since.Unix(), until.Unix())
+			if err != nil {
+				return errors.Wrap(err, "Error get list activity")
+			}
+
+			// Без активности письмо не отправляется, но неделя считается обработанной
+			if len(activity) > 0 {
+				if err := s.mailer.Send(notifications.WeeklySummaryEmail(recipient, activity)); err != nil {
+					log.Errorln(errors.Wrapf(err, "Error send weekly summary to user %s", recipient.UserId))
+
+					// Неотправленное письмо повторяется при следующем запуске, но не больше weeklySummaryMaxFailures раз
+					if err := s.repository.MarkWeeklyEmailFailed(
+						recipient.UserId, now.Unix(), weeklySummaryMaxFailures); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			if err := s.repository.MarkWeeklyEmailSent(recipient.UserId, now.Unix()); err != nil {
+				return err
+			}
+		}
+
+		if len(recipients) < weeklySummaryBatch {
+			return nil
+		}
+	}
+}
+
+// Последний наступивший момент отправки сводки
+func (s *WeeklySummarySender) lastSummaryTime(now time.Time) time.Time {
+	summary := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, time.UTC)
+	summary = summary.AddDate(0, 0, -int((7+summary.Weekday()-s.Day)%7))
+	if summary.After(now) {
+		summary = summary.AddDate(0, 0, -7)
+	}
+
+	return summary
+}
@@ -0,0 +1,181 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"shopingList/pkg/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ограничение на соединение с SMTP сервером целиком, чтобы зависший сервер не останавливал отправку
+const smtpTimeout = 30 * time.Second
+
+// Письмо с текстовой и HTML версией
+type Message struct {
+	To      string
+	ToName  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+// Отправка писем через SMTP. STARTTLS используется, если сервер его поддерживает
+type SMTPMailer struct {
+	config models.SmtpConfig
+}
+
+func NewSMTPMailer(config models.SmtpConfig) (*SMTPMailer, error) {
+	if _, err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "SMTP config is wrong")
+	}
+
+	return &SMTPMailer{config: config}, nil
+}
+
+func (s *SMTPMailer) Send(message Message) error {
+	body, err := s.build(message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	if err := s.send(auth, message.To, body); err != nil {
+		return errors.Wrap(err, "Error send email")
+	}
+
+	return nil
+}
+
+// То же, что smtp.SendMail, но с таймаутом на подключение и обмен с сервером
+func (s *SMTPMailer) send(auth smtp.Auth, to string, body []byte) error {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	conn, err := net.DialTimeout("tcp", address, smtpTimeout)
+	if err != nil {
+		return err
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.config.From); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// Собрать письмо multipart/alternative
+func (s *SMTPMailer) build(message Message) ([]byte, error) {
+	var buffer bytes.Buffer
+	parts := multipart.NewWriter(&buffer)
+
+	from := mail.Address{Name: s.config.FromName, Address: s.config.From}
+	to := mail.Address{Name: message.ToName, Address: message.To}
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + s.messageId(),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+	buffer.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{{"text/plain", message.Text}, {"text/html", message.HTML}} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (s *SMTPMailer) messageId() string {
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+
+	domain := s.config.From[strings.LastIndexByte(s.config.From, '@')+1:]
+
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
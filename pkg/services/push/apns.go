@@ -0,0 +1,210 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"shopingList/pkg/models"
+	"sync"
+	"time"
+)
+
+const apnsProductionURL = "https://api.push.apple.com/3/device/"
+const apnsSandboxURL = "https://api.sandbox.push.apple.com/3/device/"
+
+// Apple требует обновлять токен не чаще раза в 20 минут и не реже раза в час
+const apnsAuthTokenLifetime = 40 * time.Minute
+
+// Отправка напрямую в APNs с авторизацией по ключу
+type APNsProvider struct {
+	config     models.APNsConfig
+	key        *ecdsa.PrivateKey
+	url        string
+	client     *http.Client
+	mu         sync.Mutex
+	authToken  string
+	authIssued time.Time
+}
+
+func NewAPNsProvider(config models.APNsConfig) (*APNsProvider, error) {
+	if _, err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "APNs config is wrong")
+	}
+
+	data, err := ioutil.ReadFile(config.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error read APNs key")
+	}
+
+	key, err := parseAPNsKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	url := apnsSandboxURL
+	if config.Production {
+		url = apnsProductionURL
+	}
+
+	return &APNsProvider{
+		config: config,
+		key:    key,
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Ключ .p8 хранится в PKCS8
+func parseAPNsKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("APNs key must be PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parse APNs key")
+	}
+
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs key is not ECDSA key")
+	}
+
+	return key, nil
+}
+
+func (s *APNsProvider) Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error) {
+	authToken, err := s.getAuthToken()
+	if err != nil {
+		return nil, err
+	}
+
+	aps := map[string]interface{}{
+		"alert": map[string]string{"title": message.Title, "body": message.Body},
+		"sound": "default",
+	}
+	if message.Silent {
+		// Уведомление попадает в центр уведомлений без звука и не включает экран
+		delete(aps, "sound")
+		aps["interruption-level"] = "passive"
+	}
+
+	payload := map[string]interface{}{"aps": aps}
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(tokens))
+	for _, token := range tokens {
+		results = append(results, s.sendToToken(ctx, authToken, token, body))
+	}
+
+	return results, nil
+}
+
+func (s *APNsProvider) sendToToken(ctx context.Context, authToken string, token models.FCMToken, body []byte) Result {
+	request, err := http.NewRequest(http.MethodPost, s.url+token.Token, bytes.NewReader(body))
+	if err != nil {
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "bearer "+authToken)
+	request.Header.Set("apns-topic", s.config.Topic)
+	request.Header.Set("apns-push-type", "alert")
+	request.Header.Set("apns-priority", "10")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return Result{Token: token, Status: StatusRetry, Error: err.Error()}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return Result{Token: token, Status: StatusOk}
+	}
+
+	var reason struct {
+		Reason string `json:"reason"`
+	}
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	_ = json.Unmarshal(responseBody, &reason)
+
+	status := classifyAPNsError(response.StatusCode, reason.Reason)
+	if reason.Reason == "ExpiredProviderToken" || reason.Reason == "InvalidProviderToken" {
+		s.resetAuthToken()
+	}
+
+	description := reason.Reason
+	if description == "" {
+		description = http.StatusText(response.StatusCode)
+	}
+
+	return Result{Token: token, Status: status, Error: description}
+}
+
+// Определить по ответу APNs, что делать с токеном
+func classifyAPNsError(httpStatus int, reason string) string {
+	switch reason {
+	case "Unregistered", "BadDeviceToken", "DeviceTokenNotForTopic":
+		return StatusInvalidToken
+	case "ExpiredProviderToken", "InvalidProviderToken", "TooManyRequests", "InternalServerError",
+		"ServiceUnavailable", "Shutdown":
+		return StatusRetry
+	}
+
+	switch {
+	case httpStatus == http.StatusGone:
+		return StatusInvalidToken
+	case httpStatus == http.StatusTooManyRequests, httpStatus >= 500:
+		return StatusRetry
+	}
+
+	return StatusFailed
+}
+
+// JWT для APNs, кешируется на время жизни
+func (s *APNsProvider) getAuthToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.authToken != "" && now.Sub(s.authIssued) < apnsAuthTokenLifetime {
+		return s.authToken, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.config.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.config.KeyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sign APNs token")
+	}
+
+	s.authToken = signed
+	s.authIssued = now
+
+	return s.authToken, nil
+}
+
+func (s *APNsProvider) resetAuthToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authToken = ""
+}
@@ -0,0 +1,264 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"shopingList/pkg/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+const fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+
+// Сервисный аккаунт Firebase из файла credentials
+type serviceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// Отправка через FCM HTTP v1 API
+type FCMProvider struct {
+	account    serviceAccount
+	key        *rsa.PrivateKey
+	client     *http.Client
+	mu         sync.Mutex
+	authToken  string
+	authExpiry time.Time
+}
+
+func NewFCMProvider(credentialsFile string) (*FCMProvider, error) {
+	data, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error read firebase credentials")
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, errors.Wrap(err, "Error parse firebase credentials")
+	}
+
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("firebase credentials are incomplete")
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parse firebase private key")
+	}
+
+	return &FCMProvider{
+		account: account,
+		key:     key,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *FCMProvider) Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error) {
+	authToken, err := s.getAuthToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(tokens))
+	for _, token := range tokens {
+		results = append(results, s.sendToToken(ctx, authToken, token, message))
+	}
+
+	return results, nil
+}
+
+func (s *FCMProvider) sendToToken(ctx context.Context, authToken string, token models.FCMToken, message Message) Result {
+	android := map[string]interface{}{"priority": "high"}
+	aps := map[string]string{"sound": "default"}
+	if message.Silent {
+		// Уведомление остается в шторке, но без звука, вибрации и всплывающего баннера
+		android = map[string]interface{}{
+			"priority": "normal",
+			"notification": map[string]interface{}{
+				"notification_priority":   "PRIORITY_MIN",
+				"default_sound":           false,
+				"default_vibrate_timings": false,
+			},
+		}
+		aps = map[string]string{"interruption-level": "passive"}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": token.Token,
+			"notification": map[string]string{
+				"title": message.Title,
+				"body":  message.Body,
+			},
+			"data":    message.Data,
+			"android": android,
+			"apns": map[string]interface{}{
+				"payload": map[string]interface{}{"aps": aps},
+			},
+		},
+	})
+	if err != nil {
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf(fcmSendURL, s.account.ProjectID),
+		bytes.NewReader(body))
+	if err != nil {
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+authToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return Result{Token: token, Status: StatusRetry, Error: err.Error()}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return Result{Token: token, Status: StatusOk}
+	}
+
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	status, description := classifyFCMError(response.StatusCode, responseBody)
+
+	if response.StatusCode == http.StatusUnauthorized {
+		s.resetAuthToken()
+	}
+
+	return Result{Token: token, Status: status, Error: description}
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Определить по ответу FCM, что делать с токеном
+func classifyFCMError(httpStatus int, body []byte) (string, string) {
+	var response fcmErrorResponse
+	_ = json.Unmarshal(body, &response)
+
+	code := response.Error.Status
+	for _, detail := range response.Error.Details {
+		if detail.ErrorCode != "" {
+			code = detail.ErrorCode
+		}
+	}
+
+	description := strings.TrimSpace(code + " " + response.Error.Message)
+	if description == "" {
+		description = http.StatusText(httpStatus)
+	}
+
+	switch code {
+	case "UNREGISTERED", "SENDER_ID_MISMATCH", "NOT_FOUND":
+		return StatusInvalidToken, description
+	case "INVALID_ARGUMENT":
+		// Так FCM отвечает и на испорченный токен, и на ошибку в самом сообщении.
+		// Удаляется только токен, на ошибку в сообщении токены не теряются
+		if strings.Contains(strings.ToLower(response.Error.Message), "registration token") {
+			return StatusInvalidToken, description
+		}
+		return StatusFailed, description
+	case "UNAVAILABLE", "INTERNAL", "QUOTA_EXCEEDED":
+		return StatusRetry, description
+	}
+
+	switch {
+	case httpStatus == http.StatusNotFound:
+		return StatusInvalidToken, description
+	case httpStatus == http.StatusUnauthorized, httpStatus == http.StatusTooManyRequests, httpStatus >= 500:
+		return StatusRetry, description
+	}
+
+	return StatusFailed, description
+}
+
+// Access token OAuth2 для FCM, кешируется до истечения
+func (s *FCMProvider) getAuthToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.authToken != "" && time.Now().Before(s.authExpiry) {
+		return s.authToken, nil
+	}
+
+	now := time.Now()
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	assertion.Header["kid"] = s.account.PrivateKeyID
+
+	signed, err := assertion.SignedString(s.key)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sign firebase assertion")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", signed)
+
+	request, err := http.NewRequest(http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "Error get firebase access token")
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(response.Body)
+		return "", errors.New("Error get firebase access token; " + response.Status + " " + string(body))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "Error parse firebase access token")
+	}
+
+	// Обновляем заранее, чтобы токен не истек во время отправки
+	s.authToken = token.AccessToken
+	s.authExpiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+
+	return s.authToken, nil
+}
+
+func (s *FCMProvider) resetAuthToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authToken = ""
+}
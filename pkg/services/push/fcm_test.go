@@ -0,0 +1,53 @@
+package push
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassifyFCMError(t *testing.T) {
+	cases := []struct {
+		name       string
+		httpStatus int
+		body       string
+		want       string
+	}{
+		{
+			name:       "unregistered",
+			httpStatus: http.StatusNotFound,
+			body:       `{"error":{"status":"NOT_FOUND","message":"Requested entity was not found.","details":[{"errorCode":"UNREGISTERED"}]}}`,
+			want:       StatusInvalidToken,
+		},
+		{
+			name:       "malformed registration token",
+			httpStatus: http.StatusBadRequest,
+			body:       `{"error":{"status":"INVALID_ARGUMENT","message":"The registration token is not a valid FCM registration token","details":[{"errorCode":"INVALID_ARGUMENT"}]}}`,
+			want:       StatusInvalidToken,
+		},
+		{
+			name:       "invalid message field",
+			httpStatus: http.StatusBadRequest,
+			body:       `{"error":{"status":"INVALID_ARGUMENT","message":"Invalid value at 'message.data[0].value'","details":[{"errorCode":"INVALID_ARGUMENT"}]}}`,
+			want:       StatusFailed,
+		},
+		{
+			name:       "unavailable",
+			httpStatus: http.StatusServiceUnavailable,
+			body:       `{"error":{"status":"UNAVAILABLE","message":"The service is currently unavailable."}}`,
+			want:       StatusRetry,
+		},
+		{
+			name:       "empty body",
+			httpStatus: http.StatusTooManyRequests,
+			want:       StatusRetry,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if status, description := classifyFCMError(c.httpStatus, []byte(c.body)); status != c.want {
+				t.Errorf("status = %s (%s), want %s", status, description, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package push
+
+import (
+	"context"
+	"shopingList/pkg/models"
+)
+
+// Результат отправки на токен
+const (
+	StatusOk           = "ok"
+	StatusRetry        = "retry"         // Временная ошибка, отправку нужно повторить
+	StatusInvalidToken = "invalid_token" // Токен больше не действует, его нужно удалить
+	StatusFailed       = "failed"        // Отправка невозможна, повтор не поможет
+)
+
+type Message struct {
+	Title  string
+	Body   string
+	Data   map[string]string
+	Silent bool // Показать без звука и баннера, провайдер выключает звук на своей стороне
+}
+
+type Result struct {
+	Token  models.FCMToken
+	Status string
+	Error  string
+}
+
+// Сервис доставки push на устройства.
+// Ошибка возвращается, если отправка не удалась целиком, тогда повторяются все токены
+type Provider interface {
+	Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error)
+}
+
+func NewMessage(payload models.PushPayload) Message {
+	return Message{
+		Body:   payload.Message,
+		Silent: payload.Silent,
+		Data: map[string]string{
+			"type":       payload.TypeNotification,
+			"message":    payload.Message,
+			"user_id":    payload.UserId,
+			"user_phone": payload.UserPhone,
+			"list_id":    payload.ListId,
+			"item_id":    payload.ItemId,
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package push
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"shopingList/pkg/models"
+)
+
+// Отправка через провайдер, выбранный по платформе токена
+type PlatformRouter struct {
+	providers map[string]Provider
+}
+
+func NewPlatformRouter(providers map[string]Provider) *PlatformRouter {
+	return &PlatformRouter{providers: providers}
+}
+
+// Собрать провайдеры по настройкам. Один провайдер создается один раз, даже если обслуживает несколько платформ
+func NewProviderFromConfig(config models.AppConfig) (*PlatformRouter, error) {
+	if err := config.Push.Validate(); err != nil {
+		return nil, err
+	}
+
+	created := make(map[string]Provider)
+	providers := make(map[string]Provider)
+
+	for platform, name := range config.PushProviders() {
+		provider, ok := created[name]
+		if !ok {
+			var err error
+			provider, err = newProvider(name, config)
+			if err != nil {
+				return nil, errors.Wrap(err, "Error create push provider "+name)
+			}
+			created[name] = provider
+		}
+
+		providers[platform] = provider
+	}
+
+	return NewPlatformRouter(providers), nil
+}
+
+func newProvider(name string, config models.AppConfig) (Provider, error) {
+	switch name {
+	case models.PushProviderFCM:
+		return NewFCMProvider(config.FirebaseCredentialsFile)
+	case models.PushProviderAPNs:
+		return NewAPNsProvider(config.Push.APNs)
+	case models.PushProviderSink:
+		return NewSinkProvider(config.Push.SinkFile)
+	case models.PushProviderWebPush:
+		return NewWebPushProvider(config.Push.WebPush)
+	}
+
+	return nil, errors.New("unknown push provider")
+}
+
+func (s *PlatformRouter) Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error) {
+	byPlatform := make(map[string][]models.FCMToken)
+	results := make([]Result, 0, len(tokens))
+
+	for _, token := range tokens {
+		if _, ok := s.providers[token.Platform]; !ok {
+			results = append(results, Result{Token: token, Status: StatusFailed, Error: "no push provider for platform"})
+			continue
+		}
+
+		byPlatform[token.Platform] = append(byPlatform[token.Platform], token)
+	}
+
+	for platform, platformTokens := range byPlatform {
+		platformResults, err := s.providers[platform].Send(ctx, platformTokens, message)
+		if err != nil {
+			// Остальные платформы уже могли получить push, поэтому повторяются только токены этой платформы
+			for _, token := range platformTokens {
+				results = append(results, Result{Token: token, Status: StatusRetry, Error: err.Error()})
+			}
+			continue
+		}
+
+		results = append(results, platformResults...)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,99 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"shopingList/pkg/models"
+	"sync"
+	"time"
+)
+
+// Отправленный в sink push
+type SinkRecord struct {
+	Time     int64             `json:"time"`
+	Token    string            `json:"token"`
+	Platform string            `json:"platform"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data"`
+	Silent   bool              `json:"silent"`
+}
+
+// Провайдер для разработки и тестов: ничего не отправляет, а записывает каждый push.
+// С файлом пишет по JSON на строку, без файла - в лог. Последние записи доступны через Records
+type SinkProvider struct {
+	file    *os.File
+	mu      sync.Mutex
+	records []SinkRecord
+}
+
+// Сколько последних записей хранится в памяти
+const sinkRecordsLimit = 1000
+
+func NewSinkProvider(path string) (*SinkProvider, error) {
+	if path == "" {
+		return &SinkProvider{}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error open push sink file")
+	}
+
+	return &SinkProvider{file: file}, nil
+}
+
+func (s *SinkProvider) Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]Result, 0, len(tokens))
+
+	for _, token := range tokens {
+		record := SinkRecord{
+			Time:     time.Now().Unix(),
+			Token:    token.Token,
+			Platform: token.Platform,
+			Title:    message.Title,
+			Body:     message.Body,
+			Data:     message.Data,
+			Silent:   message.Silent,
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.file != nil {
+			if _, err := s.file.Write(append(line, '\n')); err != nil {
+				return nil, errors.Wrap(err, "Error write push sink file")
+			}
+		} else {
+			log.Infoln("[PUSH]: " + string(line))
+		}
+
+		s.records = append(s.records, record)
+		if len(s.records) > sinkRecordsLimit {
+			s.records = s.records[len(s.records)-sinkRecordsLimit:]
+		}
+
+		results = append(results, Result{Token: token, Status: StatusOk})
+	}
+
+	return results, nil
+}
+
+// Записанные push, начиная с самого раннего
+func (s *SinkProvider) Records() []SinkRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]SinkRecord, len(s.records))
+	copy(records, s.records)
+
+	return records
+}
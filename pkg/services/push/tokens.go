@@ -0,0 +1,48 @@
+package push
+
+import (
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+)
+
+// Токены устройств получателей
+type TokenStorage interface {
+	GetTokensForUsers(userIds []string) ([]models.FCMToken, error)
+	Delete(userId string, token models.FCMToken) error
+}
+
+// Токены мобильных устройств вместе с подписками Web Push
+type DeviceTokens struct {
+	FCM                   store.FCMTokenStorage
+	WebPushRepository     repositories.WebPushSubscriptionsRepository
+	WebPushReadRepository readModels.WebPushSubscriptionsReadRepository
+}
+
+func (s *DeviceTokens) GetTokensForUsers(userIds []string) ([]models.FCMToken, error) {
+	tokens, err := s.FCM.GetTokensForUsers(userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.WebPushReadRepository.GetTokensForUsers(userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tokens, subscriptions...), nil
+}
+
+func (s *DeviceTokens) Delete(userId string, token models.FCMToken) error {
+	if token.Platform != models.FCMTokenPlatformWeb {
+		return s.FCM.DeleteUserToken(userId, token.Token)
+	}
+
+	err := s.WebPushRepository.Delete(userId, token.Token)
+	if _, ok := err.(repositories.ErrNotFound); ok {
+		return nil
+	}
+
+	return err
+}
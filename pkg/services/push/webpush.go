@@ -0,0 +1,302 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"shopingList/pkg/models"
+	"strconv"
+	"time"
+)
+
+// Размер записи aes128gcm в заголовке сообщения
+const webPushRecordSize = 4096
+
+// Push-сервисы принимают тело запроса не больше 4096 байт. В тело кроме записи входят заголовок aes128gcm
+// (соль 16, размер записи 4, длина ключа 1, ключ 65 байт), тег GCM 16 байт и разделитель записи
+const webPushMaxPayload = 4096 - 86 - 16 - 1
+
+// Сколько push-сервис хранит сообщение, если браузер недоступен
+const webPushTTL = 24 * time.Hour
+
+// Ошибки шифрования: испорченные ключи подписки и слишком большое сообщение
+var errWebPushKeys = errors.New("subscription keys are wrong")
+var errWebPushPayloadTooLarge = errors.New("web push payload is too large")
+
+// Отправка в браузеры по протоколу Web Push с подписью VAPID (RFC 8292) и шифрованием aes128gcm (RFC 8291)
+type WebPushProvider struct {
+	config    models.WebPushConfig
+	key       *ecdsa.PrivateKey
+	publicKey string
+	client    *http.Client
+}
+
+func NewWebPushProvider(config models.WebPushConfig) (*WebPushProvider, error) {
+	if _, err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "Web Push config is wrong")
+	}
+
+	key, err := ParseVAPIDPrivateKey(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebPushProvider{
+		config:    config,
+		key:       key,
+		publicKey: VAPIDPublicKey(key),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			// Push-сервисы не перенаправляют запросы, переход по редиректу мог бы увести запрос во внутреннюю сеть
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Открытый ключ для подписки в браузере, applicationServerKey
+func (s *WebPushProvider) PublicKey() string {
+	return s.publicKey
+}
+
+func (s *WebPushProvider) Send(ctx context.Context, tokens []models.FCMToken, message Message) ([]Result, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"title":  message.Title,
+		"body":   message.Body,
+		"data":   message.Data,
+		"silent": message.Silent, // Service worker передает в showNotification
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(tokens))
+	for _, token := range tokens {
+		results = append(results, s.sendToToken(ctx, token, payload))
+	}
+
+	return results, nil
+}
+
+func (s *WebPushProvider) sendToToken(ctx context.Context, token models.FCMToken, payload []byte) Result {
+	if token.Keys == nil {
+		return Result{Token: token, Status: StatusInvalidToken, Error: "subscription keys are missing"}
+	}
+
+	// Подписки, сохраненные до проверки endpoint, удаляются
+	if !models.IsWebPushEndpointAllowed(token.Token) {
+		return Result{Token: token, Status: StatusInvalidToken, Error: "endpoint is not a known push service"}
+	}
+
+	body, err := encryptWebPush(*token.Keys, payload)
+	if err != nil {
+		// Ключи подписки испорчены, браузер все равно не расшифрует сообщение. Остальные ошибки не связаны с подпиской
+		if errors.Cause(err) == errWebPushKeys {
+			return Result{Token: token, Status: StatusInvalidToken, Error: err.Error()}
+		}
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+
+	authorization, err := s.vapidAuthorization(token.Token)
+	if err != nil {
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, token.Token, bytes.NewReader(body))
+	if err != nil {
+		return Result{Token: token, Status: StatusFailed, Error: err.Error()}
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", strconv.Itoa(int(webPushTTL/time.Second)))
+	request.Header.Set("Urgency", "high")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return Result{Token: token, Status: StatusRetry, Error: err.Error()}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return Result{Token: token, Status: StatusOk}
+	}
+
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	description := response.Status
+	if len(responseBody) > 0 {
+		description += " " + string(responseBody)
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotFound, response.StatusCode == http.StatusGone:
+		return Result{Token: token, Status: StatusInvalidToken, Error: description}
+	case response.StatusCode == http.StatusTooManyRequests, response.StatusCode >= 500:
+		return Result{Token: token, Status: StatusRetry, Error: description}
+	}
+
+	return Result{Token: token, Status: StatusFailed, Error: description}
+}
+
+// Заголовок авторизации VAPID для push-сервиса из endpoint подписки
+func (s *WebPushProvider) vapidAuthorization(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": parsed.Scheme + "://" + parsed.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": s.config.Subject,
+	})
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", errors.Wrap(err, "Error sign VAPID token")
+	}
+
+	return "vapid t=" + signed + ", k=" + s.publicKey, nil
+}
+
+// Зашифровать сообщение для подписки по RFC 8291 одной записью aes128gcm
+func encryptWebPush(keys models.WebPushKeys, plaintext []byte) ([]byte, error) {
+	// Новая пара ключей и соль на каждое сообщение
+	serverPrivate, _, _, err := elliptic.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encryptWebPushWith(keys, plaintext, salt, serverPrivate)
+}
+
+// Зашифровать сообщение с заданными солью и закрытым ключом сервера
+func encryptWebPushWith(keys models.WebPushKeys, plaintext []byte, salt []byte, serverPrivate []byte) ([]byte, error) {
+	if len(plaintext) > webPushMaxPayload {
+		return nil, errWebPushPayloadTooLarge
+	}
+
+	userPublic, err := models.DecodeWebPushKey(keys.P256dh)
+	if err != nil {
+		return nil, errors.Wrap(errWebPushKeys, "Error decode p256dh; "+err.Error())
+	}
+
+	authSecret, err := models.DecodeWebPushKey(keys.Auth)
+	if err != nil {
+		return nil, errors.Wrap(errWebPushKeys, "Error decode auth secret; "+err.Error())
+	}
+
+	curve := elliptic.P256()
+	userX, userY := elliptic.Unmarshal(curve, userPublic)
+	if userX == nil {
+		return nil, errors.Wrap(errWebPushKeys, "p256dh is not P-256 public key")
+	}
+
+	serverX, serverY := curve.ScalarBaseMult(serverPrivate)
+	serverPublic := elliptic.Marshal(curve, serverX, serverY)
+
+	sharedX, _ := curve.ScalarMult(userX, userY, serverPrivate)
+	sharedSecret := padKey(sharedX.Bytes())
+
+	keyInfo := append([]byte("WebPush: info\x00"), userPublic...)
+	keyInfo = append(keyInfo, serverPublic...)
+	ikm := hkdf(authSecret, sharedSecret, keyInfo, 32)
+
+	contentKey := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Разделитель 0x02 отмечает последнюю запись
+	record := append(append([]byte{}, plaintext...), 2)
+
+	header := make([]byte, 0, 16+4+1+len(serverPublic))
+	header = append(header, salt...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[16:20], webPushRecordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// HKDF-SHA256 для длины не больше одного блока хеша
+func hkdf(salt []byte, secret []byte, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(secret)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(sha256.New, prk)
+	expand.Write(info)
+	expand.Write([]byte{1})
+
+	return expand.Sum(nil)[:length]
+}
+
+// Закрытый ключ VAPID из base64url
+func ParseVAPIDPrivateKey(value string) (*ecdsa.PrivateKey, error) {
+	raw, err := models.DecodeWebPushKey(value)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("VAPID private key must be 32 bytes in base64url")
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(raw)}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(raw)
+
+	return key, nil
+}
+
+// Открытый ключ VAPID в base64url, несжатая точка P-256
+func VAPIDPublicKey(key *ecdsa.PrivateKey) string {
+	return base64.RawURLEncoding.EncodeToString(elliptic.Marshal(key.Curve, key.X, key.Y))
+}
+
+// Новая пара ключей VAPID: закрытый и открытый в base64url
+func GenerateVAPIDKeys() (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(padKey(key.D.Bytes())), VAPIDPublicKey(key), nil
+}
+
+// Дополнить число нулями слева до 32 байт
+func padKey(value []byte) []byte {
+	result := make([]byte, 32)
+	copy(result[32-len(value):], value)
+
+	return result
+}
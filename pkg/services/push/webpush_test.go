@@ -0,0 +1,140 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"shopingList/pkg/models"
+	"testing"
+)
+
+// Тестовый вектор из RFC 8291, Appendix A
+const (
+	rfc8291Plaintext     = "When I grow up, I want to be a watermelon"
+	rfc8291ServerPrivate = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+	rfc8291UserPrivate   = "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"
+	rfc8291UserPublic    = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	rfc8291Salt          = "DGv6ra1nlYgDCS1FRnbzlw"
+	rfc8291AuthSecret    = "BTBZMqHH6r4Tts7J_aSIgg"
+	rfc8291Body          = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+)
+
+func decodeKey(t *testing.T, value string) []byte {
+	t.Helper()
+
+	raw, err := models.DecodeWebPushKey(value)
+	if err != nil {
+		t.Fatalf("decode %s: %v", value, err)
+	}
+
+	return raw
+}
+
+func TestEncryptWebPushRFC8291(t *testing.T) {
+	keys := models.WebPushKeys{P256dh: rfc8291UserPublic, Auth: rfc8291AuthSecret}
+
+	body, err := encryptWebPushWith(keys, []byte(rfc8291Plaintext),
+		decodeKey(t, rfc8291Salt), decodeKey(t, rfc8291ServerPrivate))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := base64.RawURLEncoding.EncodeToString(body); result != rfc8291Body {
+		t.Errorf("body = %s, want %s", result, rfc8291Body)
+	}
+}
+
+func TestEncryptWebPushDecrypt(t *testing.T) {
+	keys := models.WebPushKeys{P256dh: rfc8291UserPublic, Auth: rfc8291AuthSecret}
+	plaintext := bytes.Repeat([]byte("a"), webPushMaxPayload)
+
+	body, err := encryptWebPush(keys, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body) != 4096 {
+		t.Errorf("body length = %d, want 4096", len(body))
+	}
+
+	result, err := decryptWebPush(body, decodeKey(t, rfc8291UserPrivate), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(result, plaintext) {
+		t.Error("decrypted payload differs from plaintext")
+	}
+
+	if _, err := encryptWebPush(keys, append(plaintext, 'a')); err == nil {
+		t.Error("payload over the limit must be rejected")
+	}
+}
+
+// Расшифровать сообщение так же, как браузер
+func decryptWebPush(body []byte, userPrivate []byte, keys models.WebPushKeys) ([]byte, error) {
+	curve := elliptic.P256()
+
+	salt := body[:16]
+	recordSize := binary.BigEndian.Uint32(body[16:20])
+	serverPublic := body[21 : 21+int(body[20])]
+	ciphertext := body[21+int(body[20]):]
+
+	if int(recordSize) < len(ciphertext) {
+		return nil, errors.New("record is larger than record size")
+	}
+
+	serverX, serverY := elliptic.Unmarshal(curve, serverPublic)
+	sharedX, _ := curve.ScalarMult(serverX, serverY, userPrivate)
+
+	userPublic, _ := models.DecodeWebPushKey(keys.P256dh)
+	authSecret, _ := models.DecodeWebPushKey(keys.Auth)
+
+	keyInfo := append([]byte("WebPush: info\x00"), userPublic...)
+	keyInfo = append(keyInfo, serverPublic...)
+	ikm := hkdf(authSecret, padKey(sharedX.Bytes()), keyInfo, 32)
+
+	block, err := aes.NewCipher(hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16))
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := gcm.Open(nil, hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12), ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record) == 0 || record[len(record)-1] != 2 {
+		return nil, errors.New("last record delimiter is missing")
+	}
+
+	return record[:len(record)-1], nil
+}
+
+func TestWebPushSendToTokenEncryptErrors(t *testing.T) {
+	provider := &WebPushProvider{}
+	keys := models.WebPushKeys{P256dh: rfc8291UserPublic, Auth: rfc8291AuthSecret}
+
+	token := models.FCMToken{Token: "https://fcm.googleapis.com/fcm/send/abc", Platform: models.FCMTokenPlatformWeb, Keys: &keys}
+	result := provider.sendToToken(context.Background(), token, bytes.Repeat([]byte("a"), webPushMaxPayload+1))
+	if result.Status != StatusFailed {
+		t.Errorf("payload too large: status = %s, want %s", result.Status, StatusFailed)
+	}
+
+	badKeys := models.WebPushKeys{P256dh: rfc8291AuthSecret, Auth: rfc8291AuthSecret}
+	token.Keys = &badKeys
+	result = provider.sendToToken(context.Background(), token, []byte(rfc8291Plaintext))
+	if result.Status != StatusInvalidToken {
+		t.Errorf("bad p256dh: status = %s, want %s", result.Status, StatusInvalidToken)
+	}
+}
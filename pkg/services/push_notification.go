@@ -0,0 +1,18 @@
+package services
+
+// Уведомление для отправки push
+type PushNotification interface {
+	GetTypeNotification() string
+	GetUserPhone() string
+	GetUserId() string
+	GetListId() string
+	GetItemId() string
+	GetMessage() string
+}
+
+// Push уведомления для пользователей
+type PushNotificationMessage struct {
+	Notification  PushNotification
+	TargetUserIds []string
+	Silent        bool // Отправить без звука и баннера
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
+	"shopingList/pkg/models"
+	"shopingList/pkg/readModels"
+	"shopingList/pkg/repositories"
+	"shopingList/store"
+	"time"
+)
+
+var ErrTripAlreadyActive = errors.New("list already has an active trip")
+var ErrTripIsNotActive = errors.New("trip is not active")
+
+// Походы в магазин по спискам и запись истории покупок
+type TripService struct {
+	dataService         store.DataService
+	tripsReadRepository readModels.TripsReadRepository
+}
+
+func NewTripService(dataService store.DataService, tripsReadRepository readModels.TripsReadRepository) *TripService {
+	return &TripService{dataService: dataService, tripsReadRepository: tripsReadRepository}
+}
+
+// Начать поход по списку. По списку может быть только один активный поход
+func (s *TripService) Start(user models.User, list models.List, tripId string, storeId string) (*models.Trip, error) {
+	if list.IsTemplate {
+		return nil, errors.New("forbidden to start a trip for a template list")
+	}
+
+	_, err := s.tripsReadRepository.GetActiveForList(list.ID)
+	if err == nil {
+		return nil, ErrTripAlreadyActive
+	}
+
+	if _, ok := err.(repositories.ErrNotFound); !ok {
+		return nil, pkgErrors.Wrap(err, "Error get active trip")
+	}
+
+	trip := models.Trip{
+		ID:        tripId,
+		ListID:    list.ID,
+		UserID:    user.ID,
+		Status:    models.TripStatusActive,
+		StartedAt: time.Now().UTC().Unix(),
+	}
+
+	if trip.ID == "" {
+		trip.ID = uuid.New().String()
+	}
+
+	if storeId != "" {
+		storesReadRepository := s.dataService.GetStoresReadRepository()
+		if _, err := storesReadRepository.GetStoreForOwner(storeId, user.ID); err != nil {
+			return nil, err
+		}
+
+		trip.StoreID = models.NullString{String: storeId, Valid: true}
+	}
+
+	if _, err := trip.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Проверка выше не защищает от одновременного старта с двух устройств, это гарантирует уникальный ключ
+	tripsRepository := s.dataService.GetTripsRepository(nil)
+	if err := tripsRepository.Create(&trip); err != nil {
+		if _, ok := err.(repositories.ErrDuplicate); ok {
+			return nil, ErrTripAlreadyActive
+		}
+
+		return nil, err
+	}
+
+	return &trip, nil
+}
+
+// Завершить поход: записать отмеченные товары списка в историю покупок
+// и обработать купленные товары согласно mode
+func (s *TripService) Finish(trip models.Trip, mode string) ([]models.Purchase, error) {
+	if trip.Status != models.TripStatusActive {
+		return nil, ErrTripIsNotActive
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	items, err := itemsReadRepository.GetItemsForList(trip.ListID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			return nil, pkgErrors.Wrap(err, "Error get trip items")
+		}
+	}
+
+	tx, err := s.dataService.CreateTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	tripsRepository := s.dataService.GetTripsRepository(tx)
+	purchasesRepository := s.dataService.GetPurchasesRepository(tx)
+	itemsRepository := s.dataService.GetItemsRepository(tx)
+
+	now := time.Now().UTC().Unix()
+
+	trip.Status = models.TripStatusFinished
+	trip.FinishedAt = now
+
+	// Закрыть поход первым, чтобы параллельное завершение не записало покупки дважды
+	closed, err := tripsRepository.Close(&trip)
+	if err != nil {
+		return nil, err
+	}
+
+	if !closed {
+		return nil, ErrTripIsNotActive
+	}
+
+	purchases := make([]models.Purchase, 0)
+
+	if items != nil {
+		for _, item := range *items {
+			if item.IsDeleted || !item.IsMarked {
+				continue
+			}
+
+			purchase := models.NewPurchase(uuid.New().String(), &trip, &item, now)
+			created, err := purchasesRepository.Create(&purchase)
+			if err != nil {
+				return nil, err
+			}
+
+			// Эта версия товара уже записана в прошлом походе
+			if created {
+				purchases = append(purchases, purchase)
+			}
+
+			if mode == models.TripFinishKeep {
+				continue
+			}
+
+			switch mode {
+			case models.TripFinishClear:
+				item.IsDeleted = true
+			case models.TripFinishArchive:
+				item.IsMarked = false
+				item.UserMarked = models.NullString{}
+				item.Price = models.NullFloat64{}
+				item.Currency = ""
+			}
+
+			item.UpdatedAt = now
+			item.ReceivedAt = now
+
+			if err = itemsRepository.UpdateItem(&item); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// Отменить поход без записи истории
+func (s *TripService) Cancel(trip models.Trip) error {
+	if trip.Status != models.TripStatusActive {
+		return ErrTripIsNotActive
+	}
+
+	trip.Status = models.TripStatusCancelled
+	trip.FinishedAt = time.Now().UTC().Unix()
+
+	tripsRepository := s.dataService.GetTripsRepository(nil)
+
+	closed, err := tripsRepository.Close(&trip)
+	if err != nil {
+		return err
+	}
+
+	if !closed {
+		return ErrTripIsNotActive
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package suggestions
+
+import (
+	"math"
+	"shopingList/pkg/models"
+	"sort"
+)
+
+const (
+	minDueRatio        = 0.8 // С какой доли интервала товар начинает предлагаться
+	togetherWeight     = 0.8
+	favoriteScore      = 0.3
+	favoriteBoost      = 0.1
+	DefaultSuggestions = 20
+)
+
+// Подобрать подсказки для списка по статистике покупок, парам товаров и избранному пользователя.
+// Товары, которые уже есть в списке, не предлагаются
+func Rank(
+	now int64,
+	stats []models.ProductStat,
+	pairs []models.ProductPair,
+	favorites []models.UserProduct,
+	listItems []models.ListItem,
+	limit int) []models.Suggestion {
+	inList := make(map[string]string)
+	for _, item := range listItems {
+		if !item.IsDeleted {
+			inList[ItemKey(item.ProductID, item.Name)] = item.Name
+		}
+	}
+
+	statsByKey := make(map[string]models.ProductStat)
+	for _, stat := range stats {
+		statsByKey[stat.ProductKey] = stat
+	}
+
+	result := make(map[string]models.Suggestion)
+	add := func(suggestion models.Suggestion) {
+		if _, ok := inList[suggestion.ProductKey]; ok {
+			return
+		}
+
+		if exist, ok := result[suggestion.ProductKey]; ok && exist.Score >= suggestion.Score {
+			return
+		}
+
+		result[suggestion.ProductKey] = suggestion
+	}
+
+	// Пора купить снова
+	for _, stat := range stats {
+		if stat.IntervalDays <= 0 {
+			continue
+		}
+
+		daysSince := float64(now-stat.LastBoughtAt) / secondsInDay
+		ratio := daysSince / stat.IntervalDays
+		if ratio < minDueRatio {
+			continue
+		}
+
+		add(models.Suggestion{
+			ProductKey:   stat.ProductKey,
+			Name:         stat.Name,
+			ProductID:    stat.ProductID,
+			CategoryID:   stat.CategoryID,
+			Score:        math.Min(ratio, 2) / 2,
+			Reason:       models.SuggestionReasonInterval,
+			IntervalDays: stat.IntervalDays,
+			DaysSince:    math.Round(daysSince*10) / 10,
+		})
+	}
+
+	// Часто покупается вместе с товарами из списка
+	for _, pair := range pairs {
+		relatedName, ok := inList[pair.ProductKey]
+		if !ok {
+			continue
+		}
+
+		suggestion := models.Suggestion{
+			ProductKey:  pair.RelatedKey,
+			Name:        pair.RelatedName,
+			Score:       pair.Confidence * togetherWeight,
+			Reason:      models.SuggestionReasonTogether,
+			RelatedName: relatedName,
+		}
+
+		if stat, ok := statsByKey[pair.RelatedKey]; ok {
+			suggestion.ProductID = stat.ProductID
+			suggestion.CategoryID = stat.CategoryID
+		}
+
+		add(suggestion)
+	}
+
+	// Избранное поднимает уже найденные подсказки и предлагается само с небольшим весом
+	for _, favorite := range favorites {
+		if !favorite.IsFavorite || favorite.IsDeleted {
+			continue
+		}
+
+		var productId models.NullInt64
+		if favorite.GlobalProductId != 0 {
+			productId = models.NewNullInt64(favorite.GlobalProductId)
+		}
+
+		key := ItemKey(productId, favorite.Name)
+
+		if exist, ok := result[key]; ok {
+			exist.Score = math.Min(exist.Score+favoriteBoost, 1)
+			result[key] = exist
+			continue
+		}
+
+		add(models.Suggestion{
+			ProductKey: key,
+			Name:       favorite.Name,
+			ProductID:  productId,
+			CategoryID: models.NewNullInt64(favorite.CategoryID),
+			Score:      favoriteScore,
+			Reason:     models.SuggestionReasonFavorite,
+		})
+	}
+
+	suggestions := make([]models.Suggestion, 0, len(result))
+	for _, suggestion := range result {
+		suggestion.Score = math.Round(suggestion.Score*1000) / 1000
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	if limit <= 0 {
+		limit = DefaultSuggestions
+	}
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions
+}
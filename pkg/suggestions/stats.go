@@ -0,0 +1,184 @@
+package suggestions
+
+import (
+	"shopingList/pkg/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPurchasesForInterval = 3   // Для интервала нужно хотя бы две паузы между покупками
+	minPairSupport          = 2   // Сколько раз пара должна встретиться в одном походе
+	minPairConfidence       = 0.4 // Минимальная доля походов с товаром, в которых куплен и связанный товар
+	maxPairsPerProduct      = 5
+	secondsInDay            = 24 * 60 * 60
+)
+
+// Ключ товара: товар справочника, если он известен, иначе нормализованное название
+func ItemKey(productId models.NullInt64, name string) string {
+	if productId.Valid {
+		return "p:" + strconv.FormatInt(productId.Int64, 10)
+	}
+
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+
+	return "n:" + strings.Join(strings.Fields(name), " ")
+}
+
+type productData struct {
+	stat  models.ProductStat
+	days  map[int64]bool
+	trips int
+}
+
+// Посчитать статистику покупок пользователя: интервалы повторных покупок и пары товаров, покупаемых вместе.
+// Покупки одного товара в один день считаются одной покупкой
+func BuildStats(userId string, purchases []models.Purchase) ([]models.ProductStat, []models.ProductPair) {
+	products := make(map[string]*productData)
+	baskets := make(map[string]map[string]bool)
+
+	for _, purchase := range purchases {
+		key := ItemKey(purchase.ProductID, purchase.Name)
+
+		data, ok := products[key]
+		if !ok {
+			data = &productData{
+				stat: models.ProductStat{UserID: userId, ProductKey: key},
+				days: make(map[int64]bool),
+			}
+			products[key] = data
+		}
+
+		// Название и категория берутся из последней покупки
+		if purchase.PurchasedAt >= data.stat.LastBoughtAt {
+			data.stat.LastBoughtAt = purchase.PurchasedAt
+			data.stat.Name = purchase.Name
+			data.stat.ProductID = purchase.ProductID
+			data.stat.CategoryID = purchase.CategoryID
+		}
+
+		data.days[purchase.PurchasedAt/secondsInDay] = true
+
+		basket, ok := baskets[purchase.TripID]
+		if !ok {
+			basket = make(map[string]bool)
+			baskets[purchase.TripID] = basket
+		}
+
+		if !basket[key] {
+			basket[key] = true
+			data.trips++
+		}
+	}
+
+	stats := make([]models.ProductStat, 0, len(products))
+	for _, data := range products {
+		data.stat.PurchasesCount = len(data.days)
+		data.stat.IntervalDays = medianInterval(data.days)
+		stats = append(stats, data.stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ProductKey < stats[j].ProductKey
+	})
+
+	return stats, buildPairs(userId, baskets, products)
+}
+
+func medianInterval(days map[int64]bool) float64 {
+	if len(days) < minPurchasesForInterval {
+		return 0
+	}
+
+	sorted := make([]int64, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	intervals := make([]float64, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		intervals = append(intervals, float64(sorted[i]-sorted[i-1]))
+	}
+
+	sort.Float64s(intervals)
+
+	mid := len(intervals) / 2
+	if len(intervals)%2 == 1 {
+		return intervals[mid]
+	}
+
+	return (intervals[mid-1] + intervals[mid]) / 2
+}
+
+// Посчитать пары товаров по походам. Для каждого товара остаются самые уверенные связанные товары
+func buildPairs(userId string, baskets map[string]map[string]bool, products map[string]*productData) []models.ProductPair {
+	counts := make(map[string]map[string]int)
+
+	for _, basket := range baskets {
+		for a := range basket {
+			for b := range basket {
+				if a == b {
+					continue
+				}
+
+				if _, ok := counts[a]; !ok {
+					counts[a] = make(map[string]int)
+				}
+				counts[a][b]++
+			}
+		}
+	}
+
+	pairs := make([]models.ProductPair, 0)
+
+	for a, related := range counts {
+		candidates := make([]models.ProductPair, 0)
+
+		for b, support := range related {
+			if support < minPairSupport {
+				continue
+			}
+
+			confidence := float64(support) / float64(products[a].trips)
+			if confidence < minPairConfidence {
+				continue
+			}
+
+			candidates = append(candidates, models.ProductPair{
+				UserID:      userId,
+				ProductKey:  a,
+				RelatedKey:  b,
+				RelatedName: products[b].stat.Name,
+				Support:     support,
+				Confidence:  confidence,
+			})
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Confidence != candidates[j].Confidence {
+				return candidates[i].Confidence > candidates[j].Confidence
+			}
+
+			return candidates[i].RelatedKey < candidates[j].RelatedKey
+		})
+
+		if len(candidates) > maxPairsPerProduct {
+			candidates = candidates[:maxPairsPerProduct]
+		}
+
+		pairs = append(pairs, candidates...)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].ProductKey != pairs[j].ProductKey {
+			return pairs[i].ProductKey < pairs[j].ProductKey
+		}
+
+		return pairs[i].Confidence > pairs[j].Confidence
+	})
+
+	return pairs
+}
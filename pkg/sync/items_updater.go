@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"shopingList/pkg/models"
 	"shopingList/pkg/readModels"
+	"shopingList/pkg/refbook"
 	"shopingList/pkg/repositories"
 	"time"
 )
@@ -20,6 +21,8 @@ type ItemsUpdater struct {
 	usersReadRepository  readModels.UsersReadRepository
 	notificationService  NotificationsCreateService
 	eventCollection      *EventCollection
+	productIndex         *refbook.Index
+	matcher              *refbook.Matcher
 }
 
 func NewItemsUpdater(
@@ -54,6 +57,21 @@ func (s *ItemsUpdater) Run(items []models.ListItem, lists []models.List) error {
 		return nil
 	}
 
+	for i := range items {
+		items[i].NormalizeQuantity()
+		items[i].NormalizePrice()
+	}
+
+	if s.productIndex != nil {
+		matcher, err := s.productIndex.Matcher()
+		if err != nil {
+			// Без справочника товары сохраняются как есть
+			log.Errorln(err)
+		}
+
+		s.matcher = matcher
+	}
+
 	var listIdsFormItems []string
 
 	for _, item := range items {
@@ -193,6 +211,8 @@ func (s *ItemsUpdater) updateOwnItems(items *[]models.ListItem) error {
 				return errors.New("can`t get item for id: " + item.ID)
 			}
 
+			s.resolveProduct(&item, nil)
+
 			err = s.itemsRepository.CreateItem(&item)
 			if err != nil {
 				return errors.New("Can`t create item; " + err.Error())
@@ -202,6 +222,10 @@ func (s *ItemsUpdater) updateOwnItems(items *[]models.ListItem) error {
 			continue
 		}
 
+		s.resolveProduct(&item, &existItem)
+		s.keepQuantity(&item, &existItem)
+		s.keepPrice(&item, &existItem)
+
 		if existItem.IsEqual(&item) {
 			continue
 		}
@@ -240,6 +264,8 @@ func (s *ItemsUpdater) updateItemsFromSharedList(items *[]models.ListItem) error
 				return errors.New("can`t get item for id: " + item.ID)
 			}
 
+			s.resolveProduct(&item, nil)
+
 			// Разрешаем создавать товары в пошаренных списках
 			err = s.itemsRepository.CreateItem(&item)
 			if err != nil {
@@ -250,6 +276,10 @@ func (s *ItemsUpdater) updateItemsFromSharedList(items *[]models.ListItem) error
 			continue
 		}
 
+		s.resolveProduct(&item, &existItem)
+		s.keepQuantity(&item, &existItem)
+		s.keepPrice(&item, &existItem)
+
 		if existItem.IsEqual(&item) {
 			continue
 		}
@@ -266,6 +296,75 @@ func (s *ItemsUpdater) updateItemsFromSharedList(items *[]models.ListItem) error
 	return nil
 }
 
+// Сопоставить товар со справочником.
+// Товар, указанный клиентом, не меняется. Для старых клиентов, которые не передают товар справочника,
+// сохраняется ранее найденный товар, пока не изменилось название
+func (s *ItemsUpdater) resolveProduct(item *models.ListItem, existItem *models.ListItem) {
+	item.MatchConfidence = models.NullFloat64{}
+
+	if item.ProductID.Valid {
+		if existItem != nil && existItem.ProductID == item.ProductID {
+			item.MatchConfidence = existItem.MatchConfidence
+		}
+
+		if !item.CategoryID.Valid && s.matcher != nil {
+			if product, ok := s.matcher.Product(item.ProductID.Int64); ok {
+				item.CategoryID = models.NewNullInt64(product.CategoryId)
+			}
+		}
+
+		return
+	}
+
+	if existItem != nil && existItem.ProductID.Valid && existItem.Name == item.Name {
+		item.ProductID = existItem.ProductID
+		item.CategoryID = existItem.CategoryID
+		item.MatchConfidence = existItem.MatchConfidence
+		return
+	}
+
+	if s.matcher == nil {
+		return
+	}
+
+	match, ok := s.matcher.Match(item.Name)
+	if !ok {
+		return
+	}
+
+	// Категорию, выбранную клиентом, не переопределяем
+	if item.CategoryID.Valid && item.CategoryID.Int64 != match.CategoryID {
+		return
+	}
+
+	item.ProductID = models.NewNullInt64(match.ProductID)
+	item.CategoryID = models.NewNullInt64(match.CategoryID)
+	item.MatchConfidence = models.NewNullFloat64(match.Confidence)
+}
+
+// Сохранить количество для старых клиентов, которые не передают quantity.
+// Если value не изменилось (например, не разбирается парсером), количество остается прежним
+func (s *ItemsUpdater) keepQuantity(item *models.ListItem, existItem *models.ListItem) {
+	if item.Quantity.Valid || !existItem.Quantity.Valid || item.Value != existItem.Value {
+		return
+	}
+
+	item.Quantity = existItem.Quantity
+	item.Unit = existItem.Unit
+}
+
+// Сохранить цену для старых клиентов, которые не передают price,
+// иначе каждое их изменение товара обнуляет цену и валюту.
+// Явный price: null сбрасывает цену
+func (s *ItemsUpdater) keepPrice(item *models.ListItem, existItem *models.ListItem) {
+	if item.Price.Valid || item.PriceSet || !existItem.Price.Valid {
+		return
+	}
+
+	item.Price = existItem.Price
+	item.Currency = existItem.Currency
+}
+
 func (s *ItemsUpdater) createNotificationForItem(item *models.ListItem, existItem *models.ListItem, list *models.List) {
 	listName := s.listsCollection.GetListNameById(item.ListID, list.OwnerID)
 
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"encoding/json"
+	"shopingList/pkg/models"
+	"testing"
+)
+
+func TestItemsUpdaterKeepPrice(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		wantValid bool
+		wantPrice float64
+		wantCurr  string
+	}{
+		{
+			name:      "old client without price keeps stored price",
+			body:      `{"id":"1","name":"Milk"}`,
+			wantValid: true,
+			wantPrice: 99.9,
+			wantCurr:  "RUB",
+		},
+		{
+			name:      "explicit null clears price",
+			body:      `{"id":"1","name":"Milk","price":null,"currency":""}`,
+			wantValid: false,
+		},
+		{
+			name:      "new price replaces stored price",
+			body:      `{"id":"1","name":"Milk","price":120,"currency":"USD"}`,
+			wantValid: true,
+			wantPrice: 120,
+			wantCurr:  "USD",
+		},
+	}
+
+	updater := &ItemsUpdater{}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var item models.ListItem
+			if err := json.Unmarshal([]byte(c.body), &item); err != nil {
+				t.Fatal(err)
+			}
+
+			existItem := models.ListItem{ID: "1", Name: "Milk", Price: models.NewNullFloat64(99.9), Currency: "RUB"}
+			updater.keepPrice(&item, &existItem)
+
+			if item.Price.Valid != c.wantValid || item.Price.Float64 != c.wantPrice {
+				t.Errorf("price = %+v, want valid %v and %v", item.Price, c.wantValid, c.wantPrice)
+			}
+
+			if item.Currency != c.wantCurr {
+				t.Errorf("currency = %q, want %q", item.Currency, c.wantCurr)
+			}
+		})
+	}
+}
@@ -43,6 +43,22 @@ func (s *Receiver) GetUpdates(dataService store.DataService, user models.User, u
 	}
 	resp.UserProducts = append(resp.UserProducts, userProducts...)
 
+	// Магазины
+	storesReadRepository := s.dataService.GetStoresReadRepository()
+	stores, err := storesReadRepository.GetUpdatedStoresForUser(user.ID, updatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting stores objects in receiver")
+	}
+	resp.Stores = append(resp.Stores, stores...)
+
+	// Состояния уведомлений
+	notificationsReadRepository := s.dataService.GetNotificationsReadRepository()
+	notifications, err := notificationsReadRepository.GetUpdatedStatesForUser(user.ID, updatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting notification states in receiver")
+	}
+	resp.Notifications = append(resp.Notifications, notifications...)
+
 	itemIds := make(map[string]string)
 
 	//////////////////////////////////////////////////////////////
@@ -0,0 +1,55 @@
+package sync
+
+import (
+	"errors"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"time"
+)
+
+type StoresUpdater struct {
+	user             models.User
+	storesRepository repositories.StoresRepository
+}
+
+func NewStoresUpdater(user models.User, storesRepository repositories.StoresRepository) *StoresUpdater {
+	return &StoresUpdater{user: user, storesRepository: storesRepository}
+}
+
+// Обработать магазины. Магазины не шарятся, пользователь может менять только свои
+func (s *StoresUpdater) Run(stores []models.Store) error {
+	for _, store := range stores {
+		if store.OwnerID != s.user.ID {
+			return errors.New("Forbidden to change store of another user; store: " + store.ID)
+		}
+
+		store.ReceivedAt = time.Now().UTC().Unix()
+
+		existStore, err := s.storesRepository.GetOneById(store.ID)
+		if err != nil {
+			if _, ok := err.(repositories.ErrNotFound); !ok {
+				return errors.New("can`t get store for id: " + store.ID)
+			}
+
+			if err = s.storesRepository.Create(&store); err != nil {
+				return errors.New("Can`t create store; " + err.Error())
+			}
+
+			continue
+		}
+
+		if existStore.OwnerID != s.user.ID {
+			return errors.New("Forbidden to change store of another user; store: " + store.ID)
+		}
+
+		if existStore.IsEqual(&store) {
+			continue
+		}
+
+		if err = s.storesRepository.Update(&store); err != nil {
+			return errors.New("Can`t update store; " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package sync
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	pkgErrors "github.com/pkg/errors"
+	"shopingList/pkg/models"
+	"shopingList/pkg/repositories"
+	"shopingList/pkg/units"
+	"shopingList/store"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrListIsNotTemplate = errors.New("list is not a template")
+
+const maxItemValueLength = 50
+
+// Параметры создания списка из шаблона
+type TemplateCloneOptions struct {
+	ListID           string // ID нового списка. Если пустой, то генерируется на сервере
+	Name             string // Имя нового списка. Если пустое, то берется имя шаблона
+	SkipExisting     bool   // Пропускать товары, которые уже есть в других активных списках пользователя
+	MergeQuantities  bool   // Объединять одинаковые товары шаблона в один, суммируя количество
+	ShareWithMembers bool   // Пошарить новый список на акцептованных участников шаблона
+}
+
+// Создание обычного списка из списка-шаблона
+type TemplateCloner struct {
+	dataService store.DataService
+	user        models.User
+}
+
+func NewTemplateCloner(dataService store.DataService, user models.User) *TemplateCloner {
+	return &TemplateCloner{dataService: dataService, user: user}
+}
+
+// Создать новый список из шаблона и вернуть созданные объекты в формате пакета синхронизации
+func (s *TemplateCloner) Clone(templateId string, opts TemplateCloneOptions) (*UpdatesPack, error) {
+	template, err := s.getTemplate(templateId)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	templateItems, err := itemsReadRepository.GetItemsForList(template.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); !ok {
+			return nil, pkgErrors.Wrap(err, "Error get template items")
+		}
+	}
+
+	var skipNames map[string]bool
+	if opts.SkipExisting {
+		skipNames, err = s.getActiveItemNames()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC().Unix()
+
+	list := models.List{
+		ID:         opts.ListID,
+		OwnerID:    s.user.ID,
+		Name:       opts.Name,
+		IsTemplate: false,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ReceivedAt: now,
+	}
+
+	if list.ID == "" {
+		list.ID = uuid.New().String()
+	}
+
+	if list.Name == "" {
+		list.Name = template.Name
+	}
+
+	items := make([]models.ListItem, 0)
+	itemIndexes := make(map[string]int)
+
+	if templateItems != nil {
+		for _, templateItem := range *templateItems {
+			if templateItem.IsDeleted {
+				continue
+			}
+
+			name := normalizeItemName(templateItem.Name)
+
+			if skipNames[name] {
+				continue
+			}
+
+			templateItem.NormalizeQuantity()
+
+			if ind, ok := itemIndexes[name]; ok && opts.MergeQuantities {
+				if mergeItemQuantities(&items[ind], &templateItem) {
+					continue
+				}
+			}
+
+			item := models.ListItem{
+				ID:              uuid.New().String(),
+				Name:            templateItem.Name,
+				Value:           templateItem.Value,
+				Quantity:        templateItem.Quantity,
+				Unit:            templateItem.Unit,
+				Price:           templateItem.Price,
+				Currency:        templateItem.Currency,
+				ProductID:       templateItem.ProductID,
+				CategoryID:      templateItem.CategoryID,
+				MatchConfidence: templateItem.MatchConfidence,
+				ListID:          list.ID,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+				ReceivedAt:      now,
+			}
+
+			itemIndexes[name] = len(items)
+			items = append(items, item)
+		}
+	}
+
+	shares := make([]models.ListShare, 0)
+	if opts.ShareWithMembers {
+		shares, err = s.createSharesForMembers(template, &list)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.save(&list, items, shares); err != nil {
+		return nil, err
+	}
+
+	pack := UpdatesPack{
+		Users:  []models.UserInterface{s.user},
+		Lists:  []models.List{list},
+		Items:  items,
+		Shares: shares,
+	}
+
+	return &pack, nil
+}
+
+// Вернуть шаблон, принадлежащий пользователю или пошаренный на него и акцептованный
+func (s *TemplateCloner) getTemplate(templateId string) (*models.List, error) {
+	listsReadRepository := s.dataService.GetListsReadRepository()
+
+	template, err := listsReadRepository.GetListAccessibleForUser(templateId, s.user.ID)
+	if err != nil {
+		if _, ok := err.(repositories.ErrNotFound); ok {
+			return nil, err
+		}
+
+		return nil, pkgErrors.Wrap(err, "Error get template")
+	}
+
+	if template.IsDeleted {
+		return nil, repositories.ErrNotFound{}
+	}
+
+	if !template.IsTemplate {
+		return nil, ErrListIsNotTemplate
+	}
+
+	return &template, nil
+}
+
+// Вернуть нормализованные имена неотмеченных товаров из активных списков пользователя
+func (s *TemplateCloner) getActiveItemNames() (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	listsReadRepository := s.dataService.GetListsReadRepository()
+	itemsReadRepository := s.dataService.GetItemsReadRepository()
+
+	lists, err := listsReadRepository.GetActiveListsForUser(s.user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var listIds []string
+	for _, list := range lists {
+		if !list.IsTemplate {
+			listIds = append(listIds, list.ID)
+		}
+	}
+
+	if len(listIds) == 0 {
+		return names, nil
+	}
+
+	items, err := itemsReadRepository.GetItemsForLists(listIds)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "Error get items of active lists")
+	}
+
+	for _, item := range items {
+		if item.IsDeleted || item.IsMarked {
+			continue
+		}
+
+		names[normalizeItemName(item.Name)] = true
+	}
+
+	return names, nil
+}
+
+// Подготовить акцептованные шаринги нового списка для участников шаблона.
+// Если шаблон чужой, то его владелец тоже становится участником
+func (s *TemplateCloner) createSharesForMembers(template *models.List, list *models.List) ([]models.ListShare, error) {
+	sharesReadRepository := s.dataService.GetSharesReadRepository()
+
+	memberIds, err := sharesReadRepository.GetAcceptedUserIdsFromSharedList(template.ID, template.OwnerID)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "Error get template members")
+	}
+
+	if template.OwnerID != s.user.ID {
+		memberIds = append(memberIds, template.OwnerID)
+	}
+
+	shares := make([]models.ListShare, 0)
+	for _, memberId := range memberIds {
+		if memberId == s.user.ID {
+			continue
+		}
+
+		shares = append(shares, models.ListShare{
+			ID:         uuid.New().String(),
+			ListID:     list.ID,
+			ToUserID:   memberId,
+			OwnerID:    list.OwnerID,
+			Status:     models.ShareStatusAccepted,
+			CreatedAt:  list.CreatedAt,
+			UpdatedAt:  list.UpdatedAt,
+			ReceivedAt: list.ReceivedAt,
+		})
+	}
+
+	return shares, nil
+}
+
+func (s *TemplateCloner) save(list *models.List, items []models.ListItem, shares []models.ListShare) error {
+	tx, err := s.dataService.CreateTransaction()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	listsRepository := s.dataService.GetListsRepository(tx)
+	itemsRepository := s.dataService.GetItemsRepository(tx)
+	sharesRepository := s.dataService.GetSharesRepository(tx)
+
+	if err = listsRepository.CreateList(list); err != nil {
+		return err
+	}
+
+	for i := range items {
+		if err = itemsRepository.CreateItem(&items[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range shares {
+		if err = sharesRepository.CreateShare(&shares[i]); err != nil {
+			return pkgErrors.Wrap(err, "Error create share for list from template")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func normalizeItemName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.ReplaceAll(name, "ё", "е")
+}
+
+// Прибавить количество товара src к товару dst.
+// Если количества несравнимы, то значения склеиваются через "+", пока помещаются в поле value
+func mergeItemQuantities(dst *models.ListItem, src *models.ListItem) bool {
+	if dst.Quantity.Valid && src.Quantity.Valid {
+		if sum, ok := units.Add(dst.GetQuantity(), src.GetQuantity()); ok {
+			dst.Quantity = models.NewNullFloat64(sum.Amount)
+			dst.Unit = string(sum.Unit)
+			dst.Value = units.Format(sum)
+
+			return true
+		}
+	}
+
+	if src.Value == "" {
+		return true
+	}
+
+	value := src.Value
+	if dst.Value != "" {
+		value = dst.Value + " + " + src.Value
+	}
+
+	if utf8.RuneCountInString(value) > maxItemValueLength {
+		return false
+	}
+
+	dst.Value = value
+	dst.Quantity = models.NullFloat64{}
+	dst.Unit = ""
+
+	return true
+}
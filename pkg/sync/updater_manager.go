@@ -5,6 +5,7 @@ import (
 	"log"
 	"shopingList/pkg/events"
 	"shopingList/pkg/models"
+	"shopingList/pkg/refbook"
 	"shopingList/pkg/repositories"
 	"shopingList/store"
 )
@@ -14,6 +15,7 @@ type UpdaterManager struct {
 	user            models.User
 	ChanGoodsChange chan events.GoodsChangeEvent
 	ChanShareChange chan events.ShareListEvent
+	ProductIndex    *refbook.Index // Если не задан, то товары не сопоставляются со справочником
 	eventCollection EventCollection
 }
 
@@ -23,7 +25,7 @@ func NewUpdater(dataService store.DataService, user models.User) *UpdaterManager
 		user:        user}
 }
 
-func (s *UpdaterManager) RunUpdate(users []models.User, lists []models.List, shares []models.ListShare, items []models.ListItem, userProducts []models.UserProduct) error {
+func (s *UpdaterManager) RunUpdate(users []models.User, lists []models.List, shares []models.ListShare, items []models.ListItem, userProducts []models.UserProduct, stores []models.Store) error {
 	tx, err := s.dataService.CreateTransaction()
 	if err != nil {
 		return errors.New("Error open transaction; " + err.Error())
@@ -40,6 +42,7 @@ func (s *UpdaterManager) RunUpdate(users []models.User, lists []models.List, sha
 	sharesRepository := s.dataService.GetSharesRepository(tx)
 	userProductsRepository := s.dataService.UserProductsRepository(tx)
 	userProductsReadRepository := s.dataService.UserProductsReadRepository()
+	storesRepository := s.dataService.GetStoresRepository(tx)
 	listsCollection := NewListsCollection(&listsReadRepository)
 
 	// Обновить пользователей
@@ -65,6 +68,7 @@ func (s *UpdaterManager) RunUpdate(users []models.User, lists []models.List, sha
 	// Обновить товары
 	itemsUpdater := NewItemsUpdater(s.user, listsCollection, itemsRepository, listsReadRepository,
 		sharesReadRepository, usersReadRepository, &s.eventCollection)
+	itemsUpdater.productIndex = s.ProductIndex
 
 	err = itemsUpdater.Run(items, lists)
 	if err != nil {
@@ -78,6 +82,13 @@ func (s *UpdaterManager) RunUpdate(users []models.User, lists []models.List, sha
 		return errors.New("Error update userProducts; " + err.Error())
 	}
 
+	// Обновить магазины
+	storesUpdater := NewStoresUpdater(s.user, storesRepository)
+	err = storesUpdater.Run(stores)
+	if err != nil {
+		return errors.New("Error update stores; " + err.Error())
+	}
+
 	if err = tx.Commit(); err != nil {
 		log.Fatal(errors.Wrap(err, "Error commit"))
 	}
@@ -9,6 +9,10 @@ type UpdatesPack struct {
 	Items        []models.ListItem      `json:"items"`
 	Shares       []models.ListShare     `json:"shares"`
 	UserProducts []models.UserProduct   `json:"user_products"`
+	Stores       []models.Store         `json:"stores"`
+
+	// Прочтение и удаление уведомлений на других устройствах пользователя
+	Notifications []models.NotificationState `json:"notifications"`
 }
 
 func (s *UpdatesPack) GetUserIdsInObjects() []string {
@@ -0,0 +1,130 @@
+package units
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Написания единиц измерения на русском и английском
+var unitAliases = map[string]Unit{
+	"шт": Pieces, "штук": Pieces, "штука": Pieces, "штуки": Pieces, "штучки": Pieces,
+	"pcs": Pieces, "pc": Pieces, "piece": Pieces, "pieces": Pieces, "x": Pieces, "х": Pieces,
+
+	"г": Gram, "гр": Gram, "грамм": Gram, "грамма": Gram, "граммов": Gram,
+	"g": Gram, "gr": Gram, "gram": Gram, "grams": Gram, "gramme": Gram, "grammes": Gram,
+
+	"кг": Kilogram, "кило": Kilogram, "килограмм": Kilogram, "килограмма": Kilogram, "килограммов": Kilogram,
+	"kg": Kilogram, "kilo": Kilogram, "kilos": Kilogram, "kilogram": Kilogram, "kilograms": Kilogram,
+
+	"мл": Milliliter, "миллилитр": Milliliter, "миллилитра": Milliliter, "миллилитров": Milliliter,
+	"ml": Milliliter, "milliliter": Milliliter, "milliliters": Milliliter, "millilitre": Milliliter, "millilitres": Milliliter,
+
+	"л": Liter, "литр": Liter, "литра": Liter, "литров": Liter, "литре": Liter,
+	"l": Liter, "lt": Liter, "liter": Liter, "liters": Liter, "litre": Liter, "litres": Liter,
+
+	"уп": Pack, "упак": Pack, "упаковка": Pack, "упаковки": Pack, "упаковок": Pack,
+	"пачка": Pack, "пачки": Pack, "пачек": Pack, "пакет": Pack, "пакета": Pack, "пакетов": Pack,
+	"pack": Pack, "packs": Pack, "package": Pack, "packages": Pack, "packet": Pack, "packets": Pack,
+}
+
+// Числительные, которые пишут вместо цифр
+var numberWords = map[string]float64{
+	"пол": 0.5, "половина": 0.5, "полтора": 1.5, "полторы": 1.5,
+	"один": 1, "одна": 1, "одно": 1, "два": 2, "две": 2, "три": 3, "четыре": 4, "пять": 5,
+	"шесть": 6, "семь": 7, "восемь": 8, "девять": 9, "десять": 10, "дюжина": 12,
+	"half": 0.5, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "dozen": 12,
+}
+
+// Разобрать количество из свободной строки ("2 кг", "2kg", "полтора литра", "пачка").
+// Число без единицы считается количеством штук, единица без числа - одной единицей
+func Parse(value string) (Quantity, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.ReplaceAll(value, "ё", "е")
+
+	if value == "" {
+		return Quantity{}, false
+	}
+
+	// "полкило", "пол-литра"
+	if strings.HasPrefix(value, "пол") {
+		if unit, ok := parseUnit(strings.TrimLeft(strings.TrimPrefix(value, "пол"), " -")); ok {
+			return Quantity{Amount: 0.5, Unit: unit}, true
+		}
+	}
+
+	amount, rest, ok := parseAmount(value)
+	if !ok {
+		if unit, ok := parseUnit(value); ok {
+			return Quantity{Amount: 1, Unit: unit}, true
+		}
+
+		return Quantity{}, false
+	}
+
+	if rest == "" {
+		return Quantity{Amount: amount, Unit: Pieces}, true
+	}
+
+	unit, ok := parseUnit(rest)
+	if !ok {
+		return Quantity{}, false
+	}
+
+	return Quantity{Amount: amount, Unit: unit}, true
+}
+
+// Выделить число в начале строки. Возвращает число и остаток строки
+func parseAmount(value string) (float64, string, bool) {
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != ',' && r != '/'
+	})
+
+	if end == -1 {
+		end = len(value)
+	}
+
+	if end > 0 {
+		amount, ok := parseNumber(value[:end])
+		if !ok || amount <= 0 {
+			return 0, "", false
+		}
+
+		return amount, strings.TrimSpace(value[end:]), true
+	}
+
+	fields := strings.Fields(value)
+	if amount, ok := numberWords[fields[0]]; ok {
+		return amount, strings.Join(fields[1:], " "), true
+	}
+
+	return 0, "", false
+}
+
+// Разобрать число с точкой или запятой либо простую дробь ("1/2")
+func parseNumber(value string) (float64, bool) {
+	if parts := strings.Split(value, "/"); len(parts) == 2 {
+		numerator, err1 := strconv.ParseFloat(parts[0], 64)
+		denominator, err2 := strconv.ParseFloat(parts[1], 64)
+		if err1 != nil || err2 != nil || denominator == 0 {
+			return 0, false
+		}
+
+		return numerator / denominator, true
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return amount, true
+}
+
+func parseUnit(value string) (Unit, bool) {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "."))
+
+	unit, ok := unitAliases[value]
+	return unit, ok
+}
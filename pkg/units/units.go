@@ -0,0 +1,110 @@
+package units
+
+import (
+	"math"
+	"strconv"
+)
+
+type Unit string
+
+// Каталог единиц измерения товаров
+const (
+	Pieces     Unit = "pcs"
+	Gram       Unit = "g"
+	Kilogram   Unit = "kg"
+	Milliliter Unit = "ml"
+	Liter      Unit = "l"
+	Pack       Unit = "pack"
+)
+
+var Catalog = []Unit{Pieces, Gram, Kilogram, Milliliter, Liter, Pack}
+
+// Базовая единица и множитель для перевода в нее.
+// Единицы с разными базовыми единицами не сравнимы между собой
+var baseUnits = map[Unit]struct {
+	base       Unit
+	multiplier float64
+}{
+	Pieces:     {Pieces, 1},
+	Gram:       {Gram, 1},
+	Kilogram:   {Gram, 1000},
+	Milliliter: {Milliliter, 1},
+	Liter:      {Milliliter, 1000},
+	Pack:       {Pack, 1},
+}
+
+// Краткие обозначения для вывода в поле value
+var shortTitles = map[Unit]string{
+	Pieces:     "шт",
+	Gram:       "г",
+	Kilogram:   "кг",
+	Milliliter: "мл",
+	Liter:      "л",
+	Pack:       "уп",
+}
+
+type Quantity struct {
+	Amount float64
+	Unit   Unit
+}
+
+func IsValid(unit Unit) bool {
+	_, ok := baseUnits[unit]
+	return ok
+}
+
+// Можно ли перевести одну единицу в другую
+func IsCompatible(a Unit, b Unit) bool {
+	baseA, okA := baseUnits[a]
+	baseB, okB := baseUnits[b]
+
+	return okA && okB && baseA.base == baseB.base
+}
+
+// Перевести количество в другую единицу измерения
+func Convert(q Quantity, to Unit) (Quantity, bool) {
+	if !IsCompatible(q.Unit, to) {
+		return Quantity{}, false
+	}
+
+	amount := q.Amount * baseUnits[q.Unit].multiplier / baseUnits[to].multiplier
+
+	return Quantity{Amount: round(amount), Unit: to}, true
+}
+
+// Сложить два количества. Результат приводится к удобной единице (1500 г -> 1.5 кг)
+func Add(a Quantity, b Quantity) (Quantity, bool) {
+	converted, ok := Convert(b, a.Unit)
+	if !ok {
+		return Quantity{}, false
+	}
+
+	return Normalize(Quantity{Amount: round(a.Amount + converted.Amount), Unit: a.Unit}), true
+}
+
+// Перевести граммы и миллилитры в килограммы и литры, если их больше тысячи
+func Normalize(q Quantity) Quantity {
+	switch {
+	case q.Unit == Gram && q.Amount >= 1000:
+		q, _ = Convert(q, Kilogram)
+	case q.Unit == Milliliter && q.Amount >= 1000:
+		q, _ = Convert(q, Liter)
+	}
+
+	return q
+}
+
+// Вывести количество в виде строки для поля value ("1.5 кг")
+func Format(q Quantity) string {
+	result := strconv.FormatFloat(round(q.Amount), 'f', -1, 64)
+
+	if title, ok := shortTitles[q.Unit]; ok {
+		result += " " + title
+	}
+
+	return result
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
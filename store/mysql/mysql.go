@@ -182,6 +182,14 @@ func (s *DataStore) UserProductsReadRepository() readModels.UserProductsReadRepo
 	return readModels.NewUserProductsReadRepository(s.db)
 }
 
+func (s *DataStore) GetStoresReadRepository() readModels.StoresReadRepository {
+	return readModels.NewStoresReadRepository(s.db)
+}
+
+func (s *DataStore) GetNotificationsReadRepository() readModels.NotificationsReadRepository {
+	return readModels.NewNotificationsReadRepository(s.db)
+}
+
 func (s *DataStore) GetUsersRepository(tx *sql.Tx) repositories.UsersRepository {
 	if tx != nil {
 		return repositories.NewUsersRepository(tx)
@@ -217,3 +225,51 @@ func (s *DataStore) UserProductsRepository(tx *sql.Tx) repositories.UserProducts
 
 	return repositories.NewUserProductsRepository(s.db)
 }
+
+func (s *DataStore) GetStoresRepository(tx *sql.Tx) repositories.StoresRepository {
+	if tx != nil {
+		return repositories.NewStoresRepository(tx)
+	}
+
+	return repositories.NewStoresRepository(s.db)
+}
+
+func (s *DataStore) GetTripsRepository(tx *sql.Tx) repositories.TripsRepository {
+	if tx != nil {
+		return repositories.NewTripsRepository(tx)
+	}
+
+	return repositories.NewTripsRepository(s.db)
+}
+
+func (s *DataStore) GetPurchasesRepository(tx *sql.Tx) repositories.PurchasesRepository {
+	if tx != nil {
+		return repositories.NewPurchasesRepository(tx)
+	}
+
+	return repositories.NewPurchasesRepository(s.db)
+}
+
+func (s *DataStore) GetSuggestionsRepository(tx *sql.Tx) repositories.SuggestionsRepository {
+	if tx != nil {
+		return repositories.NewSuggestionsRepository(tx)
+	}
+
+	return repositories.NewSuggestionsRepository(s.db)
+}
+
+func (s *DataStore) GetNotificationsRepository(tx *sql.Tx) repositories.NotificationsRepository {
+	if tx != nil {
+		return repositories.NewNotificationsRepository(tx)
+	}
+
+	return repositories.NewNotificationsRepository(s.db)
+}
+
+func (s *DataStore) GetPendingNotificationsRepository(tx *sql.Tx) repositories.PendingNotificationsRepository {
+	if tx != nil {
+		return repositories.NewPendingNotificationsRepository(tx)
+	}
+
+	return repositories.NewPendingNotificationsRepository(s.db)
+}
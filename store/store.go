@@ -16,6 +16,12 @@ type DataService interface {
 	GetSharesRepository(tx *sql.Tx) repositories.SharesRepository
 	GetUsersRepository(tx *sql.Tx) repositories.UsersRepository
 	UserProductsRepository(tx *sql.Tx) repositories.UserProductsRepository
+	GetStoresRepository(tx *sql.Tx) repositories.StoresRepository
+	GetTripsRepository(tx *sql.Tx) repositories.TripsRepository
+	GetPurchasesRepository(tx *sql.Tx) repositories.PurchasesRepository
+	GetSuggestionsRepository(tx *sql.Tx) repositories.SuggestionsRepository
+	GetNotificationsRepository(tx *sql.Tx) repositories.NotificationsRepository
+	GetPendingNotificationsRepository(tx *sql.Tx) repositories.PendingNotificationsRepository
 
 	// Репозитории на чтении
 	GetListsReadRepository() readModels.ListsReadRepository
@@ -23,4 +29,6 @@ type DataService interface {
 	GetSharesReadRepository() readModels.SharesReadRepository
 	GetUsersReadRepository() readModels.UsersReadRepository
 	UserProductsReadRepository() readModels.UserProductsReadRepository
+	GetStoresReadRepository() readModels.StoresReadRepository
+	GetNotificationsReadRepository() readModels.NotificationsReadRepository
 }